@@ -0,0 +1,172 @@
+package cvm
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tentameneu/cvm-go/internal/atomicfile"
+)
+
+// checkpointGenerations is number of the newest checkpoints kept in checkpoint directory.
+// Older checkpoints are kept so that resume can fall back to them when the newest one is corrupt.
+const checkpointGenerations = 3
+
+const checkpointPrefix = "checkpoint-"
+
+// ErrNoCheckpoint is returned by Resume when there is no valid checkpoint to resume from.
+var ErrNoCheckpoint = errors.New("cvm: no valid checkpoint")
+
+// A Checkpointer processes a long-running stream with a CVM and periodically persists state of the sketch to a directory,
+// together with offset of the stream supplied by the caller, so that after a restart processing can resume where it stopped.
+//
+// Every checkpoint is written to a new file with a CRC-32 checksum, through a temporary file synced and renamed into place.
+// The newest few checkpoints are kept, so Resume can skip a corrupt checkpoint and fall back to an older one.
+// Elements processed after the resumed checkpoint have to be processed again, starting from its offset.
+type Checkpointer[T any] struct {
+	sketch     *CVM[T]
+	dir        string
+	every      int
+	interval   time.Duration
+	pending    int
+	last       time.Time
+	generation uint64
+	now        func() time.Time
+}
+
+// NewCheckpointer returns new Checkpointer struct processing elements with sketch and writing checkpoints to dir,
+// which is created if it doesn't exist. Checkpoint is written after every elements processed or after interval passed
+// since the last checkpoint, whichever comes first. Zero every or interval disables that trigger.
+// Temporary files left in dir by checkpoints interrupted by a crash are removed.
+func NewCheckpointer[T any](sketch *CVM[T], dir string, every int, interval time.Duration) (*Checkpointer[T], error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	if err := atomicfile.RemoveTemp(dir); err != nil {
+		return nil, err
+	}
+	generations, err := checkpointGenerationsIn(dir)
+	if err != nil {
+		return nil, err
+	}
+	checkpointer := &Checkpointer[T]{
+		sketch:   sketch,
+		dir:      dir,
+		every:    every,
+		interval: interval,
+		now:      time.Now,
+	}
+	if len(generations) > 0 {
+		checkpointer.generation = generations[0]
+	}
+	checkpointer.last = checkpointer.now()
+	return checkpointer, nil
+}
+
+// Process element at offset of the stream with the sketch, see CVM.Process, and write a checkpoint if one is due.
+// Offset is stored in the checkpoint as is, so it should point to the next element to process after a restart.
+// Returns error only if writing the checkpoint failed, in which case element is processed and the checkpoint is retried
+// with the next element.
+func (checkpointer *Checkpointer[T]) Process(value T, offset int64) (int, error) {
+	n := checkpointer.sketch.Process(value)
+	checkpointer.pending++
+	if !checkpointer.due() {
+		return n, nil
+	}
+	return n, checkpointer.Checkpoint(offset)
+}
+
+func (checkpointer *Checkpointer[T]) due() bool {
+	if checkpointer.every > 0 && checkpointer.pending >= checkpointer.every {
+		return true
+	}
+	return checkpointer.interval > 0 && checkpointer.now().Sub(checkpointer.last) >= checkpointer.interval
+}
+
+// Checkpoint writes state of the sketch with offset right away, for example before a graceful shutdown.
+// Checkpoints older than the newest few are removed.
+func (checkpointer *Checkpointer[T]) Checkpoint(offset int64) error {
+	data, err := checkpointer.sketch.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	payload := binary.BigEndian.AppendUint64(make([]byte, 0, 8+len(data)), uint64(offset))
+	payload = append(payload, data...)
+
+	generation := checkpointer.generation + 1
+	if err := atomicfile.Write(checkpointer.path(generation), payload); err != nil {
+		return err
+	}
+	checkpointer.generation = generation
+	checkpointer.pending = 0
+	checkpointer.last = checkpointer.now()
+	return checkpointer.prune()
+}
+
+// Resume replaces state of the sketch with the newest valid checkpoint in the directory and returns its offset.
+// Corrupt checkpoints, which fail checksum verification or can't be decoded, are skipped and their number is returned as skipped.
+// Returns ErrNoCheckpoint if there is no valid checkpoint, in which case the sketch is not changed.
+func (checkpointer *Checkpointer[T]) Resume() (offset int64, skipped int, err error) {
+	generations, err := checkpointGenerationsIn(checkpointer.dir)
+	if err != nil {
+		return 0, 0, err
+	}
+	for _, generation := range generations {
+		payload, err := atomicfile.Read(checkpointer.path(generation))
+		if err != nil && !errors.Is(err, atomicfile.ErrCorrupt) {
+			return 0, skipped, err
+		}
+		if err != nil || len(payload) < 8 || checkpointer.sketch.UnmarshalBinary(payload[8:]) != nil {
+			skipped++
+			continue
+		}
+		checkpointer.pending = 0
+		checkpointer.last = checkpointer.now()
+		return int64(binary.BigEndian.Uint64(payload)), skipped, nil
+	}
+	return 0, skipped, ErrNoCheckpoint
+}
+
+func (checkpointer *Checkpointer[T]) path(generation uint64) string {
+	return filepath.Join(checkpointer.dir, fmt.Sprintf("%s%020d", checkpointPrefix, generation))
+}
+
+func (checkpointer *Checkpointer[T]) prune() error {
+	generations, err := checkpointGenerationsIn(checkpointer.dir)
+	if err != nil {
+		return err
+	}
+	for _, generation := range generations[min(checkpointGenerations, len(generations)):] {
+		if err := os.Remove(checkpointer.path(generation)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkpointGenerationsIn returns generations of checkpoints in dir, from the newest to the oldest.
+func checkpointGenerationsIn(dir string) ([]uint64, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	generations := make([]uint64, 0, len(entries))
+	for _, entry := range entries {
+		name, ok := strings.CutPrefix(entry.Name(), checkpointPrefix)
+		if !ok || len(name) != 20 {
+			continue
+		}
+		if generation, err := strconv.ParseUint(name, 10, 64); err == nil {
+			generations = append(generations, generation)
+		}
+	}
+	slices.Sort(generations)
+	slices.Reverse(generations)
+	return generations, nil
+}
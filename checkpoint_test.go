@@ -0,0 +1,126 @@
+package cvm
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestCheckpointer(t *testing.T, dir string, every int, interval time.Duration) *Checkpointer[int] {
+	checkpointer, err := NewCheckpointer(NewCVM(100, intTestComparator), dir, every, interval)
+	assert.Nil(t, err)
+	return checkpointer
+}
+
+func TestCheckpointer(t *testing.T) {
+	t.Run("Every", func(t *testing.T) {
+		dir := t.TempDir()
+		checkpointer := newTestCheckpointer(t, dir, 100, 0)
+		for i, element := range newTestIntStream(1_050, 1_000) {
+			_, err := checkpointer.Process(element, int64(i+1))
+			assert.Nil(t, err)
+		}
+		generations, err := checkpointGenerationsIn(dir)
+		assert.Nil(t, err)
+		assert.Equal(t, []uint64{10, 9, 8}, generations)
+
+		resumed := newTestCheckpointer(t, dir, 100, 0)
+		offset, skipped, err := resumed.Resume()
+		assert.Nil(t, err)
+		assert.Equal(t, int64(1_000), offset)
+		assert.Equal(t, 0, skipped)
+		assert.Equal(t, 1_000, resumed.sketch.total)
+		assert.Equal(t, uint64(10), resumed.generation)
+	})
+
+	t.Run("Interval", func(t *testing.T) {
+		dir := t.TempDir()
+		checkpointer := newTestCheckpointer(t, dir, 0, time.Minute)
+		now := time.Now()
+		checkpointer.now = func() time.Time { return now }
+		checkpointer.last = now
+		for i, element := range newTestIntStream(100, 100) {
+			if i == 50 {
+				now = now.Add(time.Minute)
+			}
+			_, err := checkpointer.Process(element, int64(i+1))
+			assert.Nil(t, err)
+		}
+		offset, _, err := newTestCheckpointer(t, dir, 0, 0).Resume()
+		assert.Nil(t, err)
+		assert.Equal(t, int64(51), offset)
+	})
+
+	t.Run("Checkpoint", func(t *testing.T) {
+		dir := t.TempDir()
+		checkpointer := newTestCheckpointer(t, dir, 0, 0)
+		for _, element := range newTestIntStream(1_000, 1_000) {
+			checkpointer.Process(element, 0)
+		}
+		assert.Nil(t, checkpointer.Checkpoint(1_000))
+
+		resumed := newTestCheckpointer(t, dir, 0, 0)
+		offset, _, err := resumed.Resume()
+		assert.Nil(t, err)
+		assert.Equal(t, int64(1_000), offset)
+		assert.Equal(t, checkpointer.sketch.Sample(), resumed.sketch.Sample())
+		assert.Equal(t, checkpointer.sketch.p, resumed.sketch.p)
+	})
+
+	t.Run("SkipCorrupt", func(t *testing.T) {
+		dir := t.TempDir()
+		checkpointer := newTestCheckpointer(t, dir, 0, 0)
+		for i := 1; i <= 3; i++ {
+			checkpointer.Process(i, 0)
+			assert.Nil(t, checkpointer.Checkpoint(int64(i)))
+		}
+		assert.Nil(t, os.WriteFile(checkpointer.path(3), []byte("torn"), 0o644))
+		data, err := os.ReadFile(checkpointer.path(2))
+		assert.Nil(t, err)
+		data[len(data)-1] ^= 0xff
+		assert.Nil(t, os.WriteFile(checkpointer.path(2), data, 0o644))
+
+		resumed := newTestCheckpointer(t, dir, 0, 0)
+		offset, skipped, err := resumed.Resume()
+		assert.Nil(t, err)
+		assert.Equal(t, int64(1), offset)
+		assert.Equal(t, 2, skipped)
+		assert.Equal(t, 1, resumed.sketch.N())
+
+		assert.Nil(t, resumed.Checkpoint(1))
+		_, err = os.Stat(resumed.path(4))
+		assert.Nil(t, err)
+	})
+
+	t.Run("RemoveTemp", func(t *testing.T) {
+		dir := t.TempDir()
+		checkpointer := newTestCheckpointer(t, dir, 0, 0)
+		checkpointer.Process(1, 0)
+		assert.Nil(t, checkpointer.Checkpoint(1))
+		stale := filepath.Join(dir, ".tmp-123")
+		assert.Nil(t, os.WriteFile(stale, []byte("torn"), 0o644))
+
+		resumed := newTestCheckpointer(t, dir, 0, 0)
+		_, err := os.Stat(stale)
+		assert.ErrorIs(t, err, os.ErrNotExist)
+		offset, skipped, err := resumed.Resume()
+		assert.Nil(t, err)
+		assert.Equal(t, int64(1), offset)
+		assert.Equal(t, 0, skipped)
+	})
+
+	t.Run("NoCheckpoint", func(t *testing.T) {
+		dir := t.TempDir()
+		assert.Nil(t, os.WriteFile(filepath.Join(dir, checkpointPrefix+"1"), nil, 0o644))
+		checkpointer := newTestCheckpointer(t, dir, 0, 0)
+		assert.Nil(t, os.WriteFile(checkpointer.path(2), nil, 0o644))
+		checkpointer.Process(1, 0)
+		_, skipped, err := checkpointer.Resume()
+		assert.ErrorIs(t, err, ErrNoCheckpoint)
+		assert.Equal(t, 1, skipped)
+		assert.Equal(t, 1, checkpointer.sketch.N())
+	})
+}
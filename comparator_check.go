@@ -0,0 +1,148 @@
+package cvm
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// ComparatorLaw is a law every Comparator must satisfy so that a treap buffer stays ordered.
+type ComparatorLaw string
+
+const (
+	// Reflexivity requires compare(x, x) == 0.
+	Reflexivity ComparatorLaw = "reflexivity"
+	// Antisymmetry requires compare(x, y) and compare(y, x) to have opposite signs.
+	Antisymmetry ComparatorLaw = "antisymmetry"
+	// Transitivity requires compare(x, y) <= 0 and compare(y, z) <= 0 to imply compare(x, z) <= 0,
+	// where compare(x, z) == 0 is allowed only if both compare(x, y) and compare(y, z) are 0.
+	Transitivity ComparatorLaw = "transitivity"
+)
+
+// ComparatorViolation is an error returned when a Comparator breaks one of the comparator laws.
+// Elements holds a concrete counterexample and Results holds comparator results for it.
+type ComparatorViolation[T any] struct {
+	Law      ComparatorLaw
+	Elements []T
+	Results  []int
+}
+
+func (violation *ComparatorViolation[T]) Error() string {
+	e, r := violation.Elements, violation.Results
+	switch violation.Law {
+	case Reflexivity:
+		return fmt.Sprintf("cvm: comparator violates %s: compare(x, x) = %d for x = %v", violation.Law, r[0], e[0])
+	case Antisymmetry:
+		return fmt.Sprintf("cvm: comparator violates %s: compare(x, y) = %d and compare(y, x) = %d for x = %v, y = %v",
+			violation.Law, r[0], r[1], e[0], e[1])
+	default:
+		return fmt.Sprintf("cvm: comparator violates %s: compare(x, y) = %d, compare(y, z) = %d and compare(x, z) = %d for x = %v, y = %v, z = %v",
+			violation.Law, r[0], r[1], r[2], e[0], e[1], e[2])
+	}
+}
+
+// CheckComparator verifies comparator laws on every element, pair and triple of elements.
+// Returns *ComparatorViolation with the first counterexample found or nil if comparator behaves on elements.
+// Number of comparisons grows with cube of len(elements), so keep the slice small (up to a few hundred elements).
+func CheckComparator[T any](comparator Comparator[T], elements []T) error {
+	for _, x := range elements {
+		if r := comparator(x, x); r != 0 {
+			return &ComparatorViolation[T]{Law: Reflexivity, Elements: []T{x}, Results: []int{r}}
+		}
+	}
+
+	for i, x := range elements {
+		for _, y := range elements[i+1:] {
+			xy, yx := comparator(x, y), comparator(y, x)
+			if sign(xy) != -sign(yx) {
+				return &ComparatorViolation[T]{Law: Antisymmetry, Elements: []T{x, y}, Results: []int{xy, yx}}
+			}
+		}
+	}
+
+	for i, x := range elements {
+		for j, y := range elements {
+			if i == j {
+				continue
+			}
+			xy := comparator(x, y)
+			if xy > 0 {
+				continue
+			}
+			for k, z := range elements {
+				if k == i || k == j {
+					continue
+				}
+				yz := comparator(y, z)
+				if yz > 0 {
+					continue
+				}
+				xz := comparator(x, z)
+				if xz > 0 || (xz == 0 && (xy != 0 || yz != 0)) {
+					return &ComparatorViolation[T]{Law: Transitivity, Elements: []T{x, y, z}, Results: []int{xy, yz, xz}}
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func sign(x int) int {
+	switch {
+	case x < 0:
+		return -1
+	case x > 0:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// A ComparatorChecker keeps uniform random sample of elements observed from stream and verifies comparator laws on it.
+// Use it while developing or debugging a Comparator against real stream data.
+type ComparatorChecker[T any] struct {
+	comparator Comparator[T]
+	sample     []T
+	sampleSize int
+	observed   int
+	random     *rand.Rand
+}
+
+// NewComparatorChecker returns new ComparatorChecker struct keeping at most sampleSize elements for verification.
+func NewComparatorChecker[T any](comparator Comparator[T], sampleSize int) *ComparatorChecker[T] {
+	return &ComparatorChecker[T]{
+		comparator: comparator,
+		sample:     make([]T, 0, sampleSize),
+		sampleSize: sampleSize,
+		observed:   0,
+	}
+}
+
+// Observe element from stream. Every observed element has the same chance to end up in checked sample.
+func (checker *ComparatorChecker[T]) Observe(value T) {
+	checker.observed++
+	if len(checker.sample) < checker.sampleSize {
+		checker.sample = append(checker.sample, value)
+		return
+	}
+	if i := checker.intn(checker.observed); i < checker.sampleSize {
+		checker.sample[i] = value
+	}
+}
+
+// SetRand sets source of random numbers used to sample observed elements. By default top-level functions of math/rand are used.
+func (checker *ComparatorChecker[T]) SetRand(random *rand.Rand) {
+	checker.random = random
+}
+
+func (checker *ComparatorChecker[T]) intn(n int) int {
+	if checker.random != nil {
+		return checker.random.Intn(n)
+	}
+	return rand.Intn(n)
+}
+
+// Check verifies comparator laws on sampled elements. See CheckComparator.
+func (checker *ComparatorChecker[T]) Check() error {
+	return CheckComparator(checker.comparator, checker.sample)
+}
@@ -0,0 +1,96 @@
+package cvm
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckComparator(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		assert.Nil(t, CheckComparator(CompareOrdered[float64], []float64{3.3, 3.9, 4.5, -1, math.NaN(), math.Inf(1)}))
+	})
+
+	t.Run("Reflexivity", func(t *testing.T) {
+		err := CheckComparator(func(x, y int) int { return 1 }, []int{1, 2})
+		var violation *ComparatorViolation[int]
+		assert.True(t, errors.As(err, &violation))
+		assert.Equal(t, Reflexivity, violation.Law)
+		assert.Equal(t, []int{1}, violation.Elements)
+		assert.Equal(t, "cvm: comparator violates reflexivity: compare(x, x) = 1 for x = 1", err.Error())
+	})
+
+	t.Run("Antisymmetry", func(t *testing.T) {
+		err := CheckComparator(func(x, y int) int {
+			if x == y {
+				return 0
+			}
+			return -1
+		}, []int{1, 2})
+		var violation *ComparatorViolation[int]
+		assert.True(t, errors.As(err, &violation))
+		assert.Equal(t, Antisymmetry, violation.Law)
+		assert.Equal(t, []int{1, 2}, violation.Elements)
+		assert.Equal(t, []int{-1, -1}, violation.Results)
+	})
+
+	t.Run("TruncatedFloat", func(t *testing.T) {
+		err := CheckComparator(floatTestComparator, []float64{3.3, 3.9, 4.5})
+		var violation *ComparatorViolation[float64]
+		assert.True(t, errors.As(err, &violation))
+		assert.Equal(t, Transitivity, violation.Law)
+		assert.Equal(t, []float64{3.3, 4.5, 3.9}, violation.Elements)
+		assert.Equal(t, []int{-1, 0, 0}, violation.Results)
+		assert.Equal(t, "cvm: comparator violates transitivity: compare(x, y) = -1, compare(y, z) = 0 and compare(x, z) = 0 for x = 3.3, y = 4.5, z = 3.9", err.Error())
+	})
+
+	t.Run("OverflowingInt", func(t *testing.T) {
+		err := CheckComparator(intTestComparator, []int{math.MinInt, 0, 1})
+		var violation *ComparatorViolation[int]
+		assert.True(t, errors.As(err, &violation))
+		assert.Equal(t, Antisymmetry, violation.Law)
+		assert.Equal(t, []int{math.MinInt, 0}, violation.Elements)
+	})
+}
+
+func TestComparatorChecker(t *testing.T) {
+	t.Run("SampleSize", func(t *testing.T) {
+		checker := NewComparatorChecker(intTestComparator, 10)
+		for _, element := range newTestIntStream(1_000, 1_000) {
+			checker.Observe(element)
+		}
+		assert.Len(t, checker.sample, 10)
+		assert.Equal(t, 1_000, checker.observed)
+		assert.Nil(t, checker.Check())
+	})
+
+	t.Run("Violation", func(t *testing.T) {
+		checker := NewComparatorChecker(floatTestComparator, 100)
+		checker.SetRand(rand.New(rand.NewSource(1)))
+		for i := 0; i < 1_000; i++ {
+			checker.Observe(float64(i) * 0.01)
+		}
+		assert.Error(t, checker.Check())
+	})
+}
+
+func TestEnableComparatorCheck(t *testing.T) {
+	t.Run("Disabled", func(t *testing.T) {
+		runner := NewCVM(10, floatTestComparator)
+		runner.Process(3.3)
+		assert.Nil(t, runner.CheckComparator())
+	})
+
+	t.Run("Enabled", func(t *testing.T) {
+		runner := NewCVM(10, floatTestComparator)
+		runner.SetRand(rand.New(rand.NewSource(1)))
+		runner.EnableComparatorCheck(100)
+		for i := 0; i < 1_000; i++ {
+			runner.Process(float64(i) * 0.01)
+		}
+		assert.Error(t, runner.CheckComparator())
+	})
+}
@@ -0,0 +1,95 @@
+package cvm
+
+import (
+	"bytes"
+	"cmp"
+	"net/netip"
+	"time"
+)
+
+// CompareOrdered is a Comparator for any ordered type (integers, floats and strings) built on cmp.Compare.
+// Unlike comparators based on subtraction (x - y) it can't overflow and it doesn't truncate floats.
+// For floats -0.0 is equal to 0.0, NaN is equal to NaN and NaN is less than any other value, including -Inf.
+func CompareOrdered[T cmp.Ordered](x, y T) int {
+	return cmp.Compare(x, y)
+}
+
+// CompareTime is a Comparator for time.Time values. Times are compared by instant, so the same
+// instant in different locations is treated as the same element.
+func CompareTime(x, y time.Time) int {
+	return x.Compare(y)
+}
+
+// CompareBytes is a Comparator for byte slices. Slices are compared lexicographically and nil is equal to an empty slice.
+func CompareBytes(x, y []byte) int {
+	return bytes.Compare(x, y)
+}
+
+// CompareAddr is a Comparator for netip.Addr values. IPv4 addresses are ordered before IPv6 addresses
+// and the zero Addr is ordered before both.
+func CompareAddr(x, y netip.Addr) int {
+	return x.Compare(y)
+}
+
+// CompareAddrPort is a Comparator for netip.AddrPort values. Values are ordered by address first and then by port.
+func CompareAddrPort(x, y netip.AddrPort) int {
+	return x.Compare(y)
+}
+
+// CompareBool is a Comparator for bool values. False is ordered before true.
+func CompareBool(x, y bool) int {
+	switch {
+	case x == y:
+		return 0
+	case !x:
+		return -1
+	default:
+		return 1
+	}
+}
+
+// NewOrderedCVM returns new CVM struct with buffer of maximum size defined with bufferSize for any ordered type.
+// Elements are compared with CompareOrdered.
+func NewOrderedCVM[T cmp.Ordered](bufferSize int) *CVM[T] {
+	return NewCVM(bufferSize, CompareOrdered[T])
+}
+
+// ByKey returns a Comparator that orders elements by an ordered key extracted with key.
+// It is useful for struct elements where identity is defined by a single field, like an ID.
+func ByKey[T any, K cmp.Ordered](key func(T) K) Comparator[T] {
+	return func(x, y T) int {
+		return cmp.Compare(key(x), key(y))
+	}
+}
+
+// ByField returns a Comparator that orders elements by a field extracted with field, compared with comparator.
+// Use it for fields that are not ordered types, like time.Time or nested structs.
+func ByField[T, F any](field func(T) F, comparator Comparator[F]) Comparator[T] {
+	return func(x, y T) int {
+		return comparator(field(x), field(y))
+	}
+}
+
+// ThenBy returns a Comparator that orders elements by comparator first and uses next only to break ties.
+// Chain it to build comparators for composite identity, like tenant and user.
+func (comparator Comparator[T]) ThenBy(next Comparator[T]) Comparator[T] {
+	return func(x, y T) int {
+		if c := comparator(x, y); c != 0 {
+			return c
+		}
+		return next(x, y)
+	}
+}
+
+// Reverse returns a Comparator with ordering opposite to comparator.
+func (comparator Comparator[T]) Reverse() Comparator[T] {
+	return func(x, y T) int {
+		return comparator(y, x)
+	}
+}
+
+// NewCVMByKey returns new CVM struct with buffer of maximum size defined with bufferSize.
+// Elements are considered the same if they have the same key extracted with key.
+func NewCVMByKey[T any, K cmp.Ordered](bufferSize int, key func(T) K) *CVM[T] {
+	return NewCVM(bufferSize, ByKey(key))
+}
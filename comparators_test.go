@@ -0,0 +1,151 @@
+package cvm
+
+import (
+	"fmt"
+	"math"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompareOrdered(t *testing.T) {
+	t.Run("Int", func(t *testing.T) {
+		assert.Equal(t, -1, CompareOrdered(1, 2))
+		assert.Equal(t, 0, CompareOrdered(2, 2))
+		assert.Equal(t, 1, CompareOrdered(3, 2))
+	})
+
+	t.Run("IntNoOverflow", func(t *testing.T) {
+		assert.Equal(t, -1, CompareOrdered(math.MinInt, 1))
+		assert.Equal(t, 1, CompareOrdered(1, math.MinInt))
+		assert.Equal(t, 1, CompareOrdered(math.MaxInt, -1))
+	})
+
+	t.Run("Float", func(t *testing.T) {
+		assert.Equal(t, -1, CompareOrdered(3.3, 3.9))
+		assert.Equal(t, 1, CompareOrdered(3.9, 3.3))
+		assert.Equal(t, 0, CompareOrdered(3.3, 3.3))
+	})
+
+	t.Run("FloatNegativeZero", func(t *testing.T) {
+		assert.Equal(t, 0, CompareOrdered(math.Copysign(0, -1), 0.0))
+	})
+
+	t.Run("FloatNaN", func(t *testing.T) {
+		assert.Equal(t, 0, CompareOrdered(math.NaN(), math.NaN()))
+		assert.Equal(t, -1, CompareOrdered(math.NaN(), math.Inf(-1)))
+		assert.Equal(t, 1, CompareOrdered(math.Inf(-1), math.NaN()))
+	})
+
+	t.Run("String", func(t *testing.T) {
+		assert.Equal(t, -1, CompareOrdered("a", "b"))
+		assert.Equal(t, 0, CompareOrdered("b", "b"))
+		assert.Equal(t, 1, CompareOrdered("c", "b"))
+	})
+}
+
+func TestCompareTime(t *testing.T) {
+	now := time.Now()
+	assert.Equal(t, -1, CompareTime(now, now.Add(time.Nanosecond)))
+	assert.Equal(t, 0, CompareTime(now, now.UTC()))
+	assert.Equal(t, 1, CompareTime(now.Add(time.Nanosecond), now))
+}
+
+func TestCompareBytes(t *testing.T) {
+	assert.Equal(t, -1, CompareBytes([]byte("a"), []byte("b")))
+	assert.Equal(t, 0, CompareBytes(nil, []byte{}))
+	assert.Equal(t, 1, CompareBytes([]byte("ab"), []byte("a")))
+}
+
+func TestCompareAddr(t *testing.T) {
+	t.Run("Addr", func(t *testing.T) {
+		assert.Equal(t, -1, CompareAddr(netip.MustParseAddr("10.0.0.1"), netip.MustParseAddr("10.0.0.2")))
+		assert.Equal(t, -1, CompareAddr(netip.MustParseAddr("10.0.0.1"), netip.MustParseAddr("::1")))
+		assert.Equal(t, 0, CompareAddr(netip.MustParseAddr("::1"), netip.MustParseAddr("::1")))
+	})
+
+	t.Run("AddrPort", func(t *testing.T) {
+		assert.Equal(t, -1, CompareAddrPort(netip.MustParseAddrPort("10.0.0.1:80"), netip.MustParseAddrPort("10.0.0.1:443")))
+		assert.Equal(t, 1, CompareAddrPort(netip.MustParseAddrPort("10.0.0.2:80"), netip.MustParseAddrPort("10.0.0.1:443")))
+	})
+}
+
+func TestCompareBool(t *testing.T) {
+	assert.Equal(t, -1, CompareBool(false, true))
+	assert.Equal(t, 0, CompareBool(true, true))
+	assert.Equal(t, 1, CompareBool(true, false))
+}
+
+func TestNewOrderedCVM(t *testing.T) {
+	t.Run("Int", func(t *testing.T) {
+		runner := NewOrderedCVM[int](10_000)
+		var n int
+		for _, element := range newTestIntStream(100_000, 1_000) {
+			n = runner.Process(element)
+		}
+		assert.Exactly(t, 1_000, n)
+	})
+
+	t.Run("Float", func(t *testing.T) {
+		runner := NewOrderedCVM[float64](10)
+		var n int
+		for _, element := range []float64{3.3, 3.9, 4.5, 3.3, 3.9} {
+			n = runner.Process(element)
+		}
+		assert.Exactly(t, 3, n)
+	})
+}
+
+type testTenantUser struct {
+	tenant  string
+	user    int
+	created time.Time
+}
+
+func TestByKey(t *testing.T) {
+	comparator := ByKey(func(x *testStruct) int { return x.id })
+	assert.Equal(t, -1, comparator(&testStruct{id: 1, name: "Bruce"}, &testStruct{id: 2, name: "Clark"}))
+	assert.Equal(t, 0, comparator(&testStruct{id: 1, name: "Bruce"}, &testStruct{id: 1, name: "Clark"}))
+	assert.Equal(t, 1, comparator(&testStruct{id: math.MaxInt, name: "Bruce"}, &testStruct{id: math.MinInt, name: "Clark"}))
+}
+
+func TestByField(t *testing.T) {
+	now := time.Now()
+	comparator := ByField(func(x testTenantUser) time.Time { return x.created }, CompareTime)
+	assert.Equal(t, -1, comparator(testTenantUser{created: now}, testTenantUser{created: now.Add(time.Second)}))
+	assert.Equal(t, 0, comparator(testTenantUser{created: now}, testTenantUser{created: now}))
+}
+
+func TestThenBy(t *testing.T) {
+	comparator := ByKey(func(x testTenantUser) string { return x.tenant }).ThenBy(ByKey(func(x testTenantUser) int { return x.user }))
+
+	t.Run("FirstKey", func(t *testing.T) {
+		assert.Equal(t, -1, comparator(testTenantUser{tenant: "a", user: 2}, testTenantUser{tenant: "b", user: 1}))
+	})
+
+	t.Run("SecondKey", func(t *testing.T) {
+		assert.Equal(t, 1, comparator(testTenantUser{tenant: "a", user: 2}, testTenantUser{tenant: "a", user: 1}))
+	})
+
+	t.Run("Equal", func(t *testing.T) {
+		assert.Equal(t, 0, comparator(testTenantUser{tenant: "a", user: 1}, testTenantUser{tenant: "a", user: 1}))
+	})
+}
+
+func TestReverse(t *testing.T) {
+	comparator := Comparator[int](CompareOrdered[int]).Reverse()
+	assert.Equal(t, 1, comparator(1, 2))
+	assert.Equal(t, 0, comparator(2, 2))
+	assert.Equal(t, -1, comparator(3, 2))
+}
+
+func TestNewCVMByKey(t *testing.T) {
+	runner := NewCVMByKey(10_000, func(x testTenantUser) string { return x.tenant })
+	var n int
+	for i := 0; i < 10_000; i++ {
+		n = runner.Process(testTenantUser{tenant: fmt.Sprint("tenant", i%100), user: i})
+	}
+	assert.Exactly(t, 100, n)
+}
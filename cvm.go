@@ -1,7 +1,11 @@
 package cvm
 
 import (
+	"errors"
+	"math"
 	"math/rand"
+
+	"github.com/tentameneu/cvm-go/internal/treap"
 )
 
 // A CVM structure is used to run CVM algorithm to estimate number of distinct elements.
@@ -10,43 +14,517 @@ type CVM[T any] struct {
 	bufferSize int
 	total      int
 	p          float64
+	checker    *ComparatorChecker[T]
+	saturated  bool
+	hooks      hooks[T]
+	algorithm  Algorithm
+	err        error
+	random     *rand.Rand
+	sizeOf     func(T) int
+	bytes      int
+	maxK       int
 }
 
+// Algorithm selects a variant of CVM algorithm used to process elements.
+type Algorithm int
+
+const (
+	// AlgorithmD is Knuth's variant from https://cs.stanford.edu/~knuth/papers/cvm-note.pdf, used by default.
+	// When buffer is full, it evicts the single element with the highest priority and lowers p to that priority.
+	AlgorithmD Algorithm = iota
+	// Algorithm1 is the original algorithm of Chakraborty, Vinodchandran and Meel.
+	// When buffer becomes full, it halves p and discards each sampled element with probability 1/2.
+	// If buffer is still full after that, the algorithm fails with ErrBufferFull, as in the paper.
+	Algorithm1
+)
+
+// ErrBufferFull is returned by Err when Algorithm1 fails, because buffer is still full after halving sampling probability.
+var ErrBufferFull = errors.New("cvm: buffer is still full after halving sampling probability")
+
 // NewCVM returns new CVM struct with buffer of maximum size defined with bufferSize.
 // Use comparator to define ordering of the elements.
 func NewCVM[T any](bufferSize int, comparator Comparator[T]) *CVM[T] {
+	return NewCVMWithAlgorithm(bufferSize, comparator, AlgorithmD)
+}
+
+// NewCVMWithAlgorithm returns new CVM struct like NewCVM, which processes elements with selected algorithm.
+func NewCVMWithAlgorithm[T any](bufferSize int, comparator Comparator[T], algorithm Algorithm) *CVM[T] {
 	return &CVM[T]{
 		buffer:     newTreapBuffer(comparator),
 		bufferSize: bufferSize,
 		total:      0,
 		p:          1.0,
+		algorithm:  algorithm,
 	}
 }
 
+// NewCVMWithByteBudget returns new CVM struct whose buffer is limited by total size of sampled elements in bytes,
+// defined with byteBudget, instead of number of elements. Size of every element is computed with sizeOf, which should
+// include per element overhead (about 64 bytes) to get a hard memory ceiling. When sampled elements don't fit into the budget,
+// elements with the highest priority are evicted and sampling probability is lowered to the last evicted priority, so the sample
+// stays a uniform sample of distinct elements as with NewCVM. Elements larger than the whole budget can never be sampled
+// and are ignored. Byte budget is supported only with AlgorithmD.
+func NewCVMWithByteBudget[T any](byteBudget int, sizeOf func(T) int, comparator Comparator[T]) *CVM[T] {
+	cvm := NewCVM(byteBudget, comparator)
+	cvm.sizeOf = sizeOf
+	return cvm
+}
+
+// Bytes returns total size in bytes of sampled elements as computed by sizeOf of NewCVMWithByteBudget.
+// Returns 0 for CVM without byte budget.
+func (cvm *CVM[T]) Bytes() int {
+	return cvm.bytes
+}
+
 // N calculates estimated number of distinct elements using current buffer status.
 func (cvm *CVM[T]) N() int {
-	return int(float64(cvm.buffer.currentSize) / cvm.p)
+	return int(float64(cvm.buffer.Size) / cvm.p)
+}
+
+// Add element from stream. It is the same as Process, without computing the estimate.
+func (cvm *CVM[T]) Add(value T) {
+	cvm.ProcessDetailed(value)
+}
+
+// Estimate returns current estimated number of distinct elements, like N but without rounding.
+func (cvm *CVM[T]) Estimate() float64 {
+	return float64(cvm.buffer.Size) / cvm.p
+}
+
+// Merge adds sample of other, which has to be *CVM[T] using the same algorithm, into this sketch.
+// Merged sampling probability is the lower of both, elements with priority at or above it are dropped and buffer is shrunk
+// as by Process until it fits bufferSize. When both samples hold the same element, the lower priority is kept.
+//
+// Merge is exact in distribution when the streams have no distinct elements in common, for example when a stream is sharded
+// by element. Elements seen in both streams are more likely to stay in the merged sample than in a sketch of the joined stream,
+// so for overlapping streams the estimate is biased upward by at most number of common elements times (1 - p).
+// Both sketches have to track occurrences with the same maxK or neither. Occurrences of an element sampled by both
+// are taken from the sample where it has the lower priority.
+// Hooks are not called by Merge.
+func (cvm *CVM[T]) Merge(other Estimator[T]) error {
+	o, ok := other.(*CVM[T])
+	if !ok || o.algorithm != cvm.algorithm || o.maxK != cvm.maxK {
+		return ErrIncompatible
+	}
+
+	cvm.p = min(cvm.p, o.p)
+	cvm.total += o.total
+	cvm.saturated = cvm.saturated || o.saturated
+	o.buffer.Ascend(func(node *treap.Node[T, *occurrences]) bool {
+		existing := cvm.buffer.Find(node.Value)
+		if existing == nil || node.Priority < existing.Priority {
+			cvm.remove(node.Value)
+			cvm.insert(node.Value, node.Priority, node.Data)
+		}
+		return true
+	})
+	for cvm.buffer.Root != nil && cvm.buffer.Root.Priority >= cvm.p {
+		cvm.evictMax()
+	}
+
+	if cvm.algorithm == Algorithm1 {
+		for cvm.buffer.Size >= cvm.bufferSize && cvm.buffer.Size > 0 {
+			cvm.p /= 2
+			for cvm.buffer.Root != nil && cvm.buffer.Root.Priority >= cvm.p {
+				cvm.evictMax()
+			}
+		}
+		if o.err != nil {
+			cvm.err = o.err
+		}
+		return nil
+	}
+	for cvm.overBudget() {
+		_, cvm.p = cvm.evictMax()
+	}
+	return nil
+}
+
+// Resize changes maximum size of buffer of a live sketch, or its budget in bytes for CVM created with NewCVMWithByteBudget.
+// Sizes below 0 are treated as 0, which empties the buffer. Growing keeps current sample and sampling probability,
+// so estimates don't change right away. As sampling probability never increases, precision improves only as new distinct
+// elements arrive and buffer fills up before p drops again. Shrinking evicts elements with the highest priority and lowers
+// sampling probability as Process does when buffer is full, so the sample still holds exactly the elements whose latest
+// draw is below p and estimates stay unbiased. The sketch can differ from one run with the smaller buffer from the start.
+// With Algorithm1 shrinking halves sampling probability until sample is smaller than bufferSize.
+// Hooks are called for elements evicted by shrinking. Resize doesn't clear Err.
+func (cvm *CVM[T]) Resize(bufferSize int) {
+	cvm.bufferSize = max(bufferSize, 0)
+	full := cvm.overBudget
+	if cvm.algorithm == Algorithm1 {
+		full = func() bool {
+			return cvm.buffer.Size >= cvm.bufferSize && cvm.buffer.Size > 0
+		}
+	}
+	if !full() {
+		return
+	}
+
+	cvm.saturate()
+	previous := cvm.p
+	var evicted []T
+	for full() {
+		if cvm.algorithm == Algorithm1 {
+			cvm.p /= 2
+			for cvm.buffer.Root != nil && cvm.buffer.Root.Priority >= cvm.p {
+				value, _ := cvm.evictMax()
+				evicted = append(evicted, value)
+			}
+			continue
+		}
+		value, priority := cvm.evictMax()
+		cvm.p = priority
+		evicted = append(evicted, value)
+	}
+	cvm.hooks.fireChange(previous, cvm.p, evicted)
+}
+
+// ProcessResult describes decisions CVM algorithm made while processing a single element.
+type ProcessResult[T any] struct {
+	// N is estimated number of distinct elements after processing element.
+	N int
+	// U is random number drawn for element. Element is kept in the sample only if U is below sampling probability.
+	U float64
+	// Seen is true if element was in the sample before processing it. See Sampled.
+	Seen bool
+	// Kept is true if element is in the sample after processing it.
+	// Seen without Kept means element was dropped from the sample because of its new draw U.
+	Kept bool
+	// PChanged is true if sampling probability decreased while processing element.
+	PChanged bool
+	// P is sampling probability after processing element.
+	P float64
+	// Evicted holds elements removed from the sample to make room in buffer, nil if nothing was evicted.
+	Evicted []T
 }
 
 // Process element from stream. Returns current estimated number of distinct elements using buffer status after processing element.
 func (cvm *CVM[T]) Process(value T) int {
-	cvm.total++
-	u := rand.Float64()
-	cvm.buffer.delete(value)
+	return cvm.ProcessDetailed(value).N
+}
+
+// ProcessSeen processes element from stream like Process. Additionally returns true if element was in the sample
+// before this call, which means it was definitely seen before. See Sampled for meaning of false.
+func (cvm *CVM[T]) ProcessSeen(value T) (int, bool) {
+	result := cvm.ProcessDetailed(value)
+	return result.N, result.Seen
+}
+
+// ProcessDetailed processes element from stream like Process. Returns ProcessResult explaining every decision
+// the algorithm made, which can be used for auditing the stream.
+func (cvm *CVM[T]) ProcessDetailed(value T) ProcessResult[T] {
+	return cvm.process(value, cvm.draw())
+}
 
-	if u >= cvm.p {
-		return cvm.N()
+// process runs a step of selected algorithm for element with random number u already drawn for it.
+func (cvm *CVM[T]) process(value T, u float64) ProcessResult[T] {
+	cvm.total++
+	if cvm.err != nil {
+		return ProcessResult[T]{N: cvm.N(), U: math.NaN(), P: cvm.p}
 	}
-	if cvm.buffer.currentSize < cvm.bufferSize {
-		cvm.buffer.insert(newNode(value, u))
-		return cvm.N()
+	if cvm.checker != nil {
+		cvm.checker.Observe(value)
 	}
-	if u > cvm.buffer.root.priority {
-		cvm.p = u
+	occurrences := cvm.nextOccurrences(value)
+	result := ProcessResult[T]{U: u, Seen: cvm.remove(value)}
+
+	if cvm.algorithm == Algorithm1 {
+		cvm.processHalving(value, occurrences, &result)
 	} else {
-		cvm.p = cvm.buffer.root.priority
-		cvm.buffer.delete(cvm.buffer.root.value)
-		cvm.buffer.insert(newNode(value, u))
+		cvm.processTreap(value, occurrences, &result)
+	}
+
+	result.N = cvm.N()
+	result.P = cvm.p
+	return result
+}
+
+// SetRand sets source of random numbers used by Process. By default CVM uses top-level functions of math/rand.
+// Use it to make runs reproducible with a fixed seed or to give independent random streams to CVMs updated from
+// different goroutines. The source must not be shared with other goroutines, as *rand.Rand is not safe for concurrent use.
+func (cvm *CVM[T]) SetRand(random *rand.Rand) {
+	cvm.random = random
+	if cvm.checker != nil {
+		cvm.checker.SetRand(random)
+	}
+}
+
+func (cvm *CVM[T]) draw() float64 {
+	if cvm.random != nil {
+		return cvm.random.Float64()
+	}
+	return rand.Float64()
+}
+
+// processTreap runs a step of Algorithm D: element is sampled if u is below p and then, while buffer is over budget,
+// element with the highest priority is evicted and p becomes its priority. Evicted element can be the new element itself.
+// With budget counted in elements at most one element is evicted.
+func (cvm *CVM[T]) processTreap(value T, occurrences *occurrences, result *ProcessResult[T]) {
+	size := cvm.elementSize(value)
+	if result.U >= cvm.p || size > cvm.bufferSize {
+		return
+	}
+	result.Kept = true
+	if cvm.used()+size <= cvm.bufferSize {
+		cvm.insert(value, result.U, occurrences)
+		return
+	}
+
+	cvm.saturate()
+	previous := cvm.p
+	cvm.insert(value, result.U, occurrences)
+	for cvm.overBudget() {
+		evicted, priority := cvm.evictMax()
+		cvm.p = priority
+		if cvm.buffer.Compare(evicted, value) == 0 {
+			result.Kept = false
+		} else {
+			result.Evicted = append(result.Evicted, evicted)
+		}
+	}
+	result.PChanged = true
+	cvm.hooks.fireChange(previous, cvm.p, result.Evicted)
+}
+
+// processHalving runs a step of Algorithm 1: when buffer becomes full, p is halved and every sampled element
+// is discarded with probability 1/2. Priorities of sampled elements are uniformly distributed below p,
+// so discarding elements with priority of at least p/2 discards each of them independently with probability 1/2.
+func (cvm *CVM[T]) processHalving(value T, occurrences *occurrences, result *ProcessResult[T]) {
+	if result.U >= cvm.p || cvm.bufferSize <= 0 {
+		return
+	}
+	cvm.insert(value, result.U, occurrences)
+	result.Kept = true
+	if cvm.buffer.Size < cvm.bufferSize {
+		return
+	}
+
+	cvm.saturate()
+	previous := cvm.p
+	cvm.p /= 2
+	for cvm.buffer.Root != nil && cvm.buffer.Root.Priority >= cvm.p {
+		evicted, _ := cvm.evictMax()
+		if cvm.buffer.Compare(evicted, value) == 0 {
+			result.Kept = false
+		} else {
+			result.Evicted = append(result.Evicted, evicted)
+		}
+	}
+	result.PChanged = true
+	cvm.hooks.fireChange(previous, cvm.p, result.Evicted)
+	if cvm.buffer.Size >= cvm.bufferSize {
+		cvm.err = ErrBufferFull
+	}
+}
+
+// elementSize returns how much of bufferSize element takes: 1 by default or its size in bytes with byte budget.
+func (cvm *CVM[T]) elementSize(value T) int {
+	if cvm.sizeOf == nil {
+		return 1
+	}
+	return cvm.sizeOf(value)
+}
+
+// used returns how much of bufferSize sampled elements take: their number by default or their size in bytes with byte budget.
+func (cvm *CVM[T]) used() int {
+	if cvm.sizeOf == nil {
+		return cvm.buffer.Size
+	}
+	return cvm.bytes
+}
+
+func (cvm *CVM[T]) overBudget() bool {
+	return cvm.used() > cvm.bufferSize
+}
+
+// insert adds element with priority and occurrences to buffer, keeping track of bytes used with byte budget.
+// Element equal to value must not be in buffer.
+func (cvm *CVM[T]) insert(value T, priority float64, occurrences *occurrences) {
+	node := newNode(value, priority)
+	node.Data = occurrences
+	cvm.buffer.Insert(node)
+	if cvm.sizeOf != nil {
+		cvm.bytes += cvm.sizeOf(value)
+	}
+}
+
+// remove deletes element equal to value from buffer, keeping track of bytes used with byte budget.
+// Returns true if such element was in buffer.
+func (cvm *CVM[T]) remove(value T) bool {
+	if cvm.sizeOf == nil {
+		return cvm.buffer.Delete(value)
+	}
+	existing := cvm.buffer.Find(value)
+	if existing == nil {
+		return false
+	}
+	cvm.bytes -= cvm.sizeOf(existing.Value)
+	return cvm.buffer.Delete(value)
+}
+
+// evictMax removes element with the highest priority from buffer. Returns removed element and its priority.
+func (cvm *CVM[T]) evictMax() (T, float64) {
+	value, priority := cvm.buffer.Root.Value, cvm.buffer.Root.Priority
+	cvm.remove(value)
+	return value, priority
+}
+
+func (cvm *CVM[T]) saturate() {
+	if !cvm.saturated {
+		cvm.saturated = true
+		cvm.hooks.fireSaturate()
+	}
+}
+
+// Err returns ErrBufferFull if Algorithm1 failed, nil otherwise. Once failed, Process doesn't change the sketch anymore.
+func (cvm *CVM[T]) Err() error {
+	return cvm.err
+}
+
+// Sampled reports whether element is currently in the sample. It doesn't modify the sketch.
+//
+// True means element was definitely seen in stream (assuming comparator is valid, see CheckComparator).
+// False means element was either never seen or it was seen but is not in the sample. While Probability is 1
+// every seen element is in the sample, so false means element was never seen. Once Probability p drops below 1,
+// a seen element is in the sample only with probability p, so false for a seen element has probability 1 - p.
+// Use it as a cheap deduplication hint, where true is certain and false is not.
+func (cvm *CVM[T]) Sampled(value T) bool {
+	return cvm.buffer.Contains(value)
+}
+
+// Probability returns current sampling probability p. Every distinct element seen in stream is in the sample
+// with probability p, independently of other elements. It starts at 1 and only decreases once buffer is full.
+func (cvm *CVM[T]) Probability() float64 {
+	return cvm.p
+}
+
+// Sample returns elements currently held in buffer, in order defined by comparator.
+// They form a uniform random sample of distinct elements seen in stream: every distinct element is included
+// with the same probability (see Probability), no matter how often it appeared in stream, so frequent elements
+// are not over-represented. For very small buffers (a handful of elements) inclusion can still depend noticeably
+// on where in stream an element appeared. While Probability is 1, the sample holds every distinct element.
+func (cvm *CVM[T]) Sample() []T {
+	sample := make([]T, 0, cvm.buffer.Size)
+	cvm.Ascend(func(value T) bool {
+		sample = append(sample, value)
+		return true
+	})
+	return sample
+}
+
+// Ascend calls yield for every element currently held in buffer in order defined by comparator, until yield returns false.
+// Elements form the same uniform sample of distinct elements as returned by Sample, without copying them.
+// Its signature matches iter.Seq.
+// Buffer must not be modified by Process during iteration.
+func (cvm *CVM[T]) Ascend(yield func(T) bool) {
+	cvm.buffer.Ascend(func(node *treap.Node[T, *occurrences]) bool {
+		return yield(node.Value)
+	})
+}
+
+// Rank estimates number of distinct elements seen in stream which are strictly less than value.
+// Sampled elements less than value are counted in O(log n) and scaled by current sampling probability.
+func (cvm *CVM[T]) Rank(value T) int {
+	return int(float64(cvm.buffer.Rank(value)) / cvm.p)
+}
+
+// NRange estimates number of distinct elements x seen in stream with lo <= x <= hi, as ordered by comparator.
+// One sketch can answer many range questions after the fact, like distinct IDs in a block or distinct timestamps in an hour.
+func (cvm *CVM[T]) NRange(lo, hi T) Estimate {
+	k := 0
+	if cvm.buffer.Compare(lo, hi) <= 0 {
+		k = cvm.buffer.Rank(hi) - cvm.buffer.Rank(lo)
+		if cvm.buffer.Contains(hi) {
+			k++
+		}
+	}
+	return newCountEstimate(k, cvm.p)
+}
+
+// NWhere estimates number of distinct elements seen in stream for which predicate returns true.
+// Predicate is evaluated on every sampled element, so one sketch can serve segment queries decided after the fact.
+// Fewer matching elements mean a smaller sample, which is reflected in larger StdErr of the returned estimate.
+func (cvm *CVM[T]) NWhere(predicate func(T) bool) Estimate {
+	k := 0
+	cvm.buffer.Ascend(func(node *treap.Node[T, *occurrences]) bool {
+		if predicate(node.Value) {
+			k++
+		}
+		return true
+	})
+	return newCountEstimate(k, cvm.p)
+}
+
+// EstimateSum estimates sum of value over distinct elements seen in stream, counting every distinct element once
+// no matter how often it appeared. Uses Horvitz-Thompson estimator: every sampled element contributes value/p.
+func (cvm *CVM[T]) EstimateSum(value func(T) float64) Estimate {
+	sum, squares, k := cvm.sampleMoments(value)
+	return Estimate{
+		Value:   sum / cvm.p,
+		StdErr:  math.Sqrt(squares*(1-cvm.p)) / cvm.p,
+		Sampled: k,
+	}
+}
+
+// sampleMoments returns sum and sum of squares of value over sampled elements, with number of sampled elements.
+func (cvm *CVM[T]) sampleMoments(value func(T) float64) (sum, squares float64, k int) {
+	cvm.buffer.Ascend(func(node *treap.Node[T, *occurrences]) bool {
+		v := value(node.Value)
+		sum += v
+		squares += v * v
+		k++
+		return true
+	})
+	return sum, squares, k
+}
+
+// EstimateMean estimates mean of value over distinct elements seen in stream, counting every distinct element once
+// no matter how often it appeared. Returns NaN as Value if buffer is empty. StdErr is NaN if it can't be estimated
+// from a single sampled element.
+func (cvm *CVM[T]) EstimateMean(value func(T) float64) Estimate {
+	sum, squares, k := cvm.sampleMoments(value)
+	if k == 0 {
+		return Estimate{Value: math.NaN(), StdErr: math.NaN(), Sampled: 0}
+	}
+
+	mean := sum / float64(k)
+	stdErr := 0.0
+	switch {
+	case cvm.p == 1.0:
+		// Every distinct element is in buffer, so the mean is exact.
+	case k == 1:
+		stdErr = math.NaN()
+	default:
+		variance := math.Max(squares-float64(k)*mean*mean, 0) / float64(k-1)
+		stdErr = math.Sqrt(variance * (1 - cvm.p) / float64(k))
+	}
+	return Estimate{Value: mean, StdErr: stdErr, Sampled: k}
+}
+
+// Quantile returns sampled element approximating q-quantile (0 <= q <= 1) over distinct elements seen in stream,
+// as ordered by comparator. Each distinct element counts once no matter how often it appeared in stream,
+// so Quantile(0.5) is the median distinct value, not the median of stream. Returns false if buffer is empty or q is out of range.
+func (cvm *CVM[T]) Quantile(q float64) (T, bool) {
+	if q < 0 || q > 1 || cvm.buffer.Size == 0 {
+		var zero T
+		return zero, false
+	}
+	k := int(math.Round(q * float64(cvm.buffer.Size-1)))
+	return cvm.buffer.Select(k).Value, true
+}
+
+// EnableComparatorCheck turns on debug mode which keeps uniform random sample of at most sampleSize processed elements
+// for verification of comparator laws with CheckComparator. Intended for debugging, as it adds overhead to Process.
+func (cvm *CVM[T]) EnableComparatorCheck(sampleSize int) {
+	cvm.checker = NewComparatorChecker(cvm.buffer.Compare, sampleSize)
+	cvm.checker.SetRand(cvm.random)
+}
+
+// CheckComparator verifies comparator laws on elements sampled since EnableComparatorCheck was called.
+// Returns *ComparatorViolation with a counterexample if comparator is broken. Returns nil if debug mode is not enabled.
+func (cvm *CVM[T]) CheckComparator() error {
+	if cvm.checker == nil {
+		return nil
 	}
-	return cvm.N()
+	return cvm.checker.Check()
 }
@@ -1,11 +1,15 @@
 package cvm
 
 import (
+	"fmt"
 	"math"
 	"math/rand"
+	"slices"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/tentameneu/cvm-go/internal/treap"
 )
 
 func TestRun(t *testing.T) {
@@ -28,6 +32,630 @@ func TestRun(t *testing.T) {
 	})
 }
 
+func TestSetRand(t *testing.T) {
+	runWithSeed := func(seed int64) []int {
+		runner := NewCVM(100, intTestComparator)
+		runner.SetRand(rand.New(rand.NewSource(seed)))
+		for _, element := range newTestIntStream(10_000, 1_000) {
+			runner.Process(element)
+		}
+		return runner.Sample()
+	}
+
+	assert.Equal(t, runWithSeed(42), runWithSeed(42))
+	assert.NotEqual(t, runWithSeed(42), runWithSeed(43))
+}
+
+func TestAlgorithm1(t *testing.T) {
+	t.Run("SmallerBuffer", func(t *testing.T) {
+		runner := NewCVMWithAlgorithm(1_000, intTestComparator, Algorithm1)
+		var n int
+		for _, element := range newTestIntStream(1_000_000, 10_000) {
+			n = runner.Process(element)
+		}
+		assert.Nil(t, runner.Err())
+		assert.InDelta(t, 10_000, n, 1_500)
+	})
+
+	t.Run("ExactBuffer", func(t *testing.T) {
+		runner := NewCVMWithAlgorithm(10_001, intTestComparator, Algorithm1)
+		var n int
+		for _, element := range newTestIntStream(1_000_000, 10_000) {
+			n = runner.Process(element)
+		}
+		assert.Nil(t, runner.Err())
+		assert.Exactly(t, 10_000, n)
+	})
+
+	t.Run("Invariants", func(t *testing.T) {
+		runner := NewCVMWithAlgorithm(100, intTestComparator, Algorithm1)
+		for _, element := range newTestIntStream(100_000, 10_000) {
+			result := runner.ProcessDetailed(element)
+			assert.Nil(t, runner.Err())
+			assert.Nil(t, runner.buffer.Validate())
+			assert.Less(t, runner.buffer.Size, runner.bufferSize)
+			_, exponent := math.Frexp(result.P)
+			assert.Equal(t, math.Ldexp(0.5, exponent), result.P)
+			if runner.buffer.Root != nil {
+				assert.Less(t, runner.buffer.Root.Priority, runner.p)
+			}
+			assert.Equal(t, result.Kept, runner.Sampled(element))
+		}
+	})
+
+	t.Run("Halving", func(t *testing.T) {
+		// Buffer fills up with the 4th element, so p is always halved. Repeat if every element survived and algorithm failed.
+		var runner *CVM[int]
+		var result ProcessResult[int]
+		for runner == nil || runner.Err() != nil {
+			runner = NewCVMWithAlgorithm(4, intTestComparator, Algorithm1)
+			for _, element := range []int{1, 2, 3} {
+				runner.Process(element)
+			}
+			result = runner.ProcessDetailed(4)
+		}
+		assert.True(t, result.PChanged)
+		assert.Equal(t, 0.5, result.P)
+		expectedSize := 4 - len(result.Evicted)
+		if !result.Kept {
+			expectedSize--
+		}
+		assert.Equal(t, expectedSize, runner.buffer.Size)
+		for _, evicted := range result.Evicted {
+			assert.False(t, runner.Sampled(evicted))
+		}
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		runner := NewCVMWithAlgorithm(1, intTestComparator, Algorithm1)
+		for i := 0; runner.Err() == nil; i++ {
+			runner.Process(i)
+		}
+		assert.ErrorIs(t, runner.Err(), ErrBufferFull)
+		sample, p := runner.Sample(), runner.p
+		result := runner.ProcessDetailed(-1)
+		assert.False(t, result.Kept)
+		assert.Equal(t, sample, runner.Sample())
+		assert.Equal(t, p, runner.p)
+	})
+}
+
+func TestByteBudget(t *testing.T) {
+	sizeOf := func(x string) int { return len(x) }
+	newVariableStringStream := func(total, distinct int) ([]string, float64) {
+		elements := make([]string, distinct)
+		totalLength := 0
+		for i := range elements {
+			elements[i] = fmt.Sprintf("%d:%s", i, strings.Repeat("x", rand.Intn(1_000)))
+			totalLength += len(elements[i])
+		}
+		stream := make([]string, total)
+		for i := range stream {
+			stream[i] = elements[i%distinct]
+		}
+		return stream, float64(totalLength) / float64(distinct)
+	}
+	sampleBytes := func(runner *CVM[string]) int {
+		bytes := 0
+		for _, element := range runner.Sample() {
+			bytes += len(element)
+		}
+		return bytes
+	}
+
+	t.Run("ExactBuffer", func(t *testing.T) {
+		runner := NewCVMWithByteBudget(1_000, sizeOf, stringTestComparator)
+		var n int
+		for _, element := range newTestStringStream(10_000, 100) {
+			n = runner.Process(element)
+		}
+		assert.Exactly(t, 100, n)
+		assert.Equal(t, 190, runner.Bytes())
+	})
+
+	t.Run("SmallerBuffer", func(t *testing.T) {
+		stream, meanLength := newVariableStringStream(100_000, 10_000)
+		runner := NewCVMWithByteBudget(500_000, sizeOf, stringTestComparator)
+		for i, element := range stream {
+			runner.Process(element)
+			if i%1_000 == 0 {
+				assert.LessOrEqual(t, runner.Bytes(), 500_000)
+				assert.Equal(t, sampleBytes(runner), runner.Bytes())
+			}
+		}
+		assert.Nil(t, runner.buffer.Validate())
+		assert.Less(t, runner.p, 1.0)
+		assert.InDelta(t, 10_000, runner.N(), 1_500)
+		// Sample is not biased towards short elements.
+		assert.InDelta(t, meanLength, runner.EstimateMean(func(x string) float64 { return float64(len(x)) }).Value, 0.1*meanLength)
+	})
+
+	t.Run("Oversized", func(t *testing.T) {
+		runner := NewCVMWithByteBudget(10, sizeOf, stringTestComparator)
+		runner.Process("Bruce")
+		result := runner.ProcessDetailed("Bruce Wayne")
+		assert.False(t, result.Kept)
+		assert.Nil(t, result.Evicted)
+		assert.Equal(t, []string{"Bruce"}, runner.Sample())
+		assert.Equal(t, 1.0, runner.p)
+	})
+
+	t.Run("EvictsSeveral", func(t *testing.T) {
+		runner := NewCVMWithByteBudget(10, sizeOf, stringTestComparator)
+		runner.SetRand(rand.New(rand.NewSource(1)))
+		for _, element := range []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j"} {
+			runner.Process(element)
+		}
+		result := runner.ProcessDetailed("0123456789")
+		assert.True(t, result.PChanged)
+		assert.LessOrEqual(t, runner.Bytes(), 10)
+		assert.Equal(t, sampleBytes(runner), runner.Bytes())
+		if result.Kept {
+			assert.Len(t, result.Evicted, 10)
+		}
+	})
+
+	t.Run("Marshal", func(t *testing.T) {
+		runner := NewCVMWithByteBudget(100, sizeOf, stringTestComparator)
+		for _, element := range newTestStringStream(1_000, 100) {
+			runner.Process(element)
+		}
+		data, err := runner.MarshalBinary()
+		assert.Nil(t, err)
+		decoded := NewCVMWithByteBudget(0, sizeOf, stringTestComparator)
+		assert.Nil(t, decoded.UnmarshalBinary(data))
+		assert.Equal(t, runner.Bytes(), decoded.Bytes())
+		assert.Equal(t, 100, decoded.bufferSize)
+	})
+
+	t.Run("Merge", func(t *testing.T) {
+		first, second := NewCVMWithByteBudget(100, sizeOf, stringTestComparator), NewCVMWithByteBudget(100, sizeOf, stringTestComparator)
+		for i, element := range newTestStringStream(1_000, 200) {
+			if i%2 == 0 {
+				first.Process(element)
+			} else {
+				second.Process(element)
+			}
+		}
+		assert.Nil(t, first.Merge(second))
+		assert.LessOrEqual(t, first.Bytes(), 100)
+		assert.Equal(t, sampleBytes(first), first.Bytes())
+	})
+}
+
+func TestResize(t *testing.T) {
+	t.Run("Shrink", func(t *testing.T) {
+		runner := NewCVM(2_000, intTestComparator)
+		stream := newTestIntStream(100_000, 10_000)
+		for _, element := range stream[:50_000] {
+			runner.Process(element)
+		}
+		previous := runner.p
+		runner.Resize(500)
+		assert.Less(t, runner.p, previous)
+		assert.Equal(t, 500, runner.buffer.Size)
+		assert.Nil(t, runner.buffer.Validate())
+		runner.buffer.Ascend(func(node *treap.Node[int, *occurrences]) bool {
+			assert.Less(t, node.Priority, runner.p)
+			return true
+		})
+		for _, element := range stream[50_000:] {
+			runner.Process(element)
+		}
+		assert.LessOrEqual(t, runner.buffer.Size, 500)
+		assert.InDelta(t, 10_000, runner.N(), 2_000)
+	})
+
+	t.Run("ShrinkNotFull", func(t *testing.T) {
+		runner := NewCVM(100, intTestComparator)
+		for _, element := range newTestIntStream(100, 10) {
+			runner.Process(element)
+		}
+		runner.Resize(10)
+		assert.Equal(t, 1.0, runner.p)
+		assert.Equal(t, 10, runner.N())
+	})
+
+	t.Run("Grow", func(t *testing.T) {
+		runner := NewCVM(100, intTestComparator)
+		stream := newTestIntStream(100_000, 10_000)
+		for _, element := range stream[:50_000] {
+			runner.Process(element)
+		}
+		sample, p := runner.Sample(), runner.p
+		runner.Resize(1_000)
+		assert.Equal(t, sample, runner.Sample())
+		assert.Equal(t, p, runner.p)
+		for _, element := range stream[50_000:] {
+			runner.Process(element + 10_000)
+		}
+		assert.Greater(t, runner.buffer.Size, 100)
+		assert.LessOrEqual(t, runner.buffer.Size, 1_000)
+		assert.InDelta(t, 20_000, runner.N(), 4_000)
+	})
+
+	t.Run("Algorithm1", func(t *testing.T) {
+		runner := NewCVMWithAlgorithm(2_000, intTestComparator, Algorithm1)
+		for _, element := range newTestIntStream(20_000, 10_000) {
+			runner.Process(element)
+		}
+		previous := runner.p
+		runner.Resize(500)
+		assert.Less(t, runner.buffer.Size, 500)
+		assert.Less(t, runner.p, previous)
+		assert.Nil(t, runner.Err())
+		assert.InDelta(t, 10_000, runner.N(), 3_000)
+	})
+
+	for _, test := range []struct {
+		name      string
+		algorithm Algorithm
+	}{{"NegativeAlgorithmD", AlgorithmD}, {"NegativeAlgorithm1", Algorithm1}} {
+		t.Run(test.name, func(t *testing.T) {
+			runner := NewCVMWithAlgorithm(100, intTestComparator, test.algorithm)
+			for _, element := range newTestIntStream(1_000, 500) {
+				runner.Process(element)
+			}
+			runner.Resize(-1)
+			assert.Equal(t, 0, runner.bufferSize)
+			assert.Equal(t, 0, runner.buffer.Size)
+			assert.Nil(t, runner.buffer.Validate())
+			for _, element := range newTestIntStream(100, 100) {
+				assert.False(t, runner.ProcessDetailed(element).Kept)
+			}
+			assert.Equal(t, 0, runner.buffer.Size)
+			assert.Nil(t, runner.Err())
+		})
+	}
+
+	t.Run("ByteBudget", func(t *testing.T) {
+		runner := NewCVMWithByteBudget(1_000, func(x string) int { return len(x) }, stringTestComparator)
+		for _, element := range newTestStringStream(10_000, 1_000) {
+			runner.Process(element)
+		}
+		runner.Resize(100)
+		assert.LessOrEqual(t, runner.Bytes(), 100)
+	})
+
+	t.Run("Hooks", func(t *testing.T) {
+		runner := NewCVM(100, intTestComparator)
+		for _, element := range newTestIntStream(100, 100) {
+			runner.Process(element)
+		}
+		saturated, evicted, changes := 0, 0, 0
+		runner.OnSaturate(func() {
+			saturated++
+			assert.Equal(t, 100, runner.buffer.Size)
+		})
+		runner.OnEvict(func(value int) { evicted++ })
+		runner.OnProbabilityChange(func(previous, current float64) {
+			changes++
+			assert.Equal(t, 1.0, previous)
+			assert.Equal(t, runner.p, current)
+		})
+		runner.Resize(10)
+		assert.Equal(t, 1, saturated)
+		assert.Equal(t, 90, evicted)
+		assert.Equal(t, 1, changes)
+	})
+}
+
+func TestMerge(t *testing.T) {
+	t.Run("ExactBuffer", func(t *testing.T) {
+		first, second := NewCVM(1_000, intTestComparator), NewCVM(1_000, intTestComparator)
+		for i, element := range newTestIntStream(10_000, 800) {
+			if i%3 == 0 {
+				first.Process(element)
+			} else {
+				second.Process(element)
+			}
+		}
+		assert.Nil(t, first.Merge(second))
+		assert.Nil(t, first.buffer.Validate())
+		assert.Exactly(t, 800, first.N())
+		assert.Equal(t, 10_000, first.total)
+	})
+
+	t.Run("Shrinks", func(t *testing.T) {
+		first, second := NewCVM(100, intTestComparator), NewCVM(100, intTestComparator)
+		for element := 0; element < 100; element++ {
+			first.Process(element)
+			second.Process(element + 100)
+		}
+		assert.Nil(t, first.Merge(second))
+		assert.Nil(t, first.buffer.Validate())
+		assert.Equal(t, 100, first.buffer.Size)
+		assert.Less(t, first.p, 1.0)
+		assert.Less(t, first.buffer.Root.Priority, first.p)
+	})
+
+	t.Run("ShardedStream", func(t *testing.T) {
+		shards := []*CVM[int]{NewCVM(1_000, intTestComparator), NewCVM(1_000, intTestComparator), NewCVM(1_000, intTestComparator)}
+		for _, element := range newTestIntStream(100_000, 30_000) {
+			shards[element%3].Process(element)
+		}
+		assert.Nil(t, shards[0].Merge(shards[1]))
+		assert.Nil(t, shards[0].Merge(shards[2]))
+		assert.Nil(t, shards[0].buffer.Validate())
+		assert.InDelta(t, 30_000, shards[0].N(), 3_000)
+	})
+
+	t.Run("Algorithm1", func(t *testing.T) {
+		first, second := NewCVMWithAlgorithm(100, intTestComparator, Algorithm1), NewCVMWithAlgorithm(100, intTestComparator, Algorithm1)
+		for _, element := range newTestIntStream(10_000, 1_000) {
+			if element%2 == 0 {
+				first.Process(element)
+			} else {
+				second.Process(element)
+			}
+		}
+		assert.Nil(t, first.Merge(second))
+		assert.Nil(t, first.buffer.Validate())
+		assert.Less(t, first.buffer.Size, 100)
+		_, exponent := math.Frexp(first.p)
+		assert.Equal(t, math.Ldexp(0.5, exponent), first.p)
+	})
+
+	t.Run("DifferentAlgorithm", func(t *testing.T) {
+		assert.ErrorIs(t, NewCVM(10, intTestComparator).Merge(NewCVMWithAlgorithm(10, intTestComparator, Algorithm1)), ErrIncompatible)
+	})
+}
+
+func TestProcessDetailed(t *testing.T) {
+	t.Run("NotFull", func(t *testing.T) {
+		runner := NewCVM(2, intTestComparator)
+		result := runner.ProcessDetailed(3)
+		assert.Equal(t, 1, result.N)
+		assert.False(t, result.Seen)
+		assert.True(t, result.Kept)
+		assert.False(t, result.PChanged)
+		assert.Equal(t, 1.0, result.P)
+		assert.Nil(t, result.Evicted)
+		assert.Equal(t, result.U, runner.buffer.Root.Priority)
+
+		result = runner.ProcessDetailed(3)
+		assert.Equal(t, 1, result.N)
+		assert.True(t, result.Seen)
+		assert.True(t, result.Kept)
+		assert.Equal(t, result.U, runner.buffer.Root.Priority)
+	})
+
+	t.Run("Full", func(t *testing.T) {
+		runner := NewCVM(2, intTestComparator)
+		runner.Process(1)
+		runner.Process(2)
+		maxPriority := runner.buffer.Root.Priority
+		maxValue := runner.buffer.Root.Value
+
+		result := runner.ProcessDetailed(3)
+		assert.True(t, result.PChanged)
+		assert.Less(t, result.P, 1.0)
+		if result.U > maxPriority {
+			assert.False(t, result.Kept)
+			assert.Nil(t, result.Evicted)
+			assert.Equal(t, result.U, result.P)
+		} else {
+			assert.True(t, result.Kept)
+			assert.Equal(t, []int{maxValue}, result.Evicted)
+			assert.Equal(t, maxPriority, result.P)
+		}
+		assert.Equal(t, runner.N(), result.N)
+	})
+
+	// Every processed element is explained by the result: dropped, kept or evicted elements must match the buffer.
+	t.Run("Audit", func(t *testing.T) {
+		runner := NewCVM(100, intTestComparator)
+		sample := make(map[int]bool)
+		p := 1.0
+		for _, element := range newTestIntStream(10_000, 1_000) {
+			result := runner.ProcessDetailed(element)
+			assert.Equal(t, sample[element], result.Seen)
+			assert.Equal(t, result.U < result.P, result.Kept)
+			assert.Equal(t, result.P < p, result.PChanged)
+			delete(sample, element)
+			if result.Kept {
+				sample[element] = true
+			}
+			for _, evicted := range result.Evicted {
+				delete(sample, evicted)
+			}
+			p = result.P
+		}
+		assert.Len(t, sample, runner.buffer.Size)
+		for element := range sample {
+			assert.True(t, runner.Sampled(element))
+		}
+	})
+}
+
+func TestSampled(t *testing.T) {
+	t.Run("ExactBuffer", func(t *testing.T) {
+		runner := NewCVM(10, intTestComparator)
+		for _, element := range []int{3, 4, 1} {
+			runner.Process(element)
+		}
+		assert.True(t, runner.Sampled(3))
+		assert.True(t, runner.Sampled(1))
+		assert.False(t, runner.Sampled(2))
+		assert.Equal(t, 3, runner.N())
+	})
+
+	t.Run("SmallerBuffer", func(t *testing.T) {
+		runner := NewCVM(100, intTestComparator)
+		for _, element := range newTestIntStream(10_000, 1_000) {
+			runner.Process(element)
+		}
+		sampled := 0
+		for element := 0; element < 1_000; element++ {
+			if runner.Sampled(element) {
+				sampled++
+			}
+		}
+		assert.Equal(t, runner.buffer.Size, sampled)
+		assert.False(t, runner.Sampled(-1))
+	})
+}
+
+func TestProcessSeen(t *testing.T) {
+	t.Run("ExactBuffer", func(t *testing.T) {
+		runner := NewCVM(10, intTestComparator)
+		n, seen := runner.ProcessSeen(3)
+		assert.Equal(t, 1, n)
+		assert.False(t, seen)
+		n, seen = runner.ProcessSeen(4)
+		assert.Equal(t, 2, n)
+		assert.False(t, seen)
+		n, seen = runner.ProcessSeen(3)
+		assert.Equal(t, 2, n)
+		assert.True(t, seen)
+	})
+
+	t.Run("SmallerBuffer", func(t *testing.T) {
+		runner := NewCVM(100, intTestComparator)
+		for _, element := range newTestIntStream(10_000, 1_000) {
+			wasSampled := runner.Sampled(element)
+			_, seen := runner.ProcessSeen(element)
+			assert.Equal(t, wasSampled, seen)
+		}
+	})
+}
+
+func TestSample(t *testing.T) {
+	t.Run("Empty", func(t *testing.T) {
+		runner := NewCVM(10, intTestComparator)
+		assert.Equal(t, []int{}, runner.Sample())
+		assert.Equal(t, 1.0, runner.Probability())
+	})
+
+	t.Run("ExactBuffer", func(t *testing.T) {
+		runner := NewCVM(10, intTestComparator)
+		for _, element := range []int{3, 4, 1, 3, 2, 8, 9, 6, 7, 5} {
+			runner.Process(element)
+		}
+		assert.Equal(t, []int{1, 2, 3, 4, 5, 6, 7, 8, 9}, runner.Sample())
+		assert.Equal(t, 1.0, runner.Probability())
+	})
+
+	t.Run("SmallerBuffer", func(t *testing.T) {
+		runner := NewCVM(100, intTestComparator)
+		for _, element := range newTestIntStream(100_000, 10_000) {
+			runner.Process(element)
+		}
+		sample := runner.Sample()
+		assert.Len(t, sample, runner.buffer.Size)
+		assert.True(t, slices.IsSorted(sample))
+		assert.Less(t, runner.Probability(), 1.0)
+	})
+
+	// Distinct elements should end up in the sample equally often, no matter how often they appear in stream.
+	// Elements are grouped by number of occurrences (from 1 up to 82) and groups are compared.
+	t.Run("Uniform", func(t *testing.T) {
+		stream := make([]int, 0)
+		for element := 0; element < 100; element++ {
+			for i := 0; i <= (element%10)*(element%10); i++ {
+				stream = append(stream, element)
+			}
+		}
+		rand.Shuffle(len(stream), func(i, j int) { stream[i], stream[j] = stream[j], stream[i] })
+
+		counts := make([]int, 10)
+		for i := 0; i < 2_000; i++ {
+			runner := NewCVM(30, intTestComparator)
+			for _, element := range stream {
+				runner.Process(element)
+			}
+			for _, element := range runner.Sample() {
+				counts[element%10]++
+			}
+		}
+		mean := float64(sumInts(counts)) / 10
+		for group := 0; group < 10; group++ {
+			assert.InDelta(t, mean, counts[group], 0.1*mean, "group %d", group)
+		}
+	})
+}
+
+func sumInts(values []int) int {
+	total := 0
+	for _, value := range values {
+		total += value
+	}
+	return total
+}
+
+func TestAscendSample(t *testing.T) {
+	runner := NewCVM(10, intTestComparator)
+	for _, element := range []int{3, 4, 1, 3, 2} {
+		runner.Process(element)
+	}
+	values := make([]int, 0)
+	runner.Ascend(func(value int) bool {
+		values = append(values, value)
+		return value < 3
+	})
+	assert.Equal(t, []int{1, 2, 3}, values)
+}
+
+func TestEstimatedRank(t *testing.T) {
+	t.Run("ExactBuffer", func(t *testing.T) {
+		runner := NewCVM(1_000, intTestComparator)
+		for _, element := range newTestIntStream(10_000, 1_000) {
+			runner.Process(element)
+		}
+		assert.Exactly(t, 0, runner.Rank(0))
+		assert.Exactly(t, 500, runner.Rank(500))
+		assert.Exactly(t, 1_000, runner.Rank(5_000))
+	})
+
+	t.Run("SmallerBuffer", func(t *testing.T) {
+		runner := NewCVM(1_000, intTestComparator)
+		runner.SetRand(rand.New(rand.NewSource(1)))
+		for _, element := range newTestIntStream(100_000, 10_000) {
+			runner.Process(element)
+		}
+		assert.InDelta(t, 2_500, runner.Rank(2_500), 500)
+	})
+}
+
+func TestQuantile(t *testing.T) {
+	t.Run("Empty", func(t *testing.T) {
+		runner := NewCVM(10, intTestComparator)
+		_, ok := runner.Quantile(0.5)
+		assert.False(t, ok)
+	})
+
+	t.Run("OutOfRange", func(t *testing.T) {
+		runner := NewCVM(10, intTestComparator)
+		runner.Process(1)
+		_, ok := runner.Quantile(1.5)
+		assert.False(t, ok)
+	})
+
+	t.Run("DistinctNotFrequency", func(t *testing.T) {
+		runner := NewCVM(10, intTestComparator)
+		for _, element := range []int{1, 1, 1, 1, 1, 1, 2, 3, 4, 5} {
+			runner.Process(element)
+		}
+		median, ok := runner.Quantile(0.5)
+		assert.True(t, ok)
+		assert.Equal(t, 3, median)
+		minimum, _ := runner.Quantile(0)
+		assert.Equal(t, 1, minimum)
+		maximum, _ := runner.Quantile(1)
+		assert.Equal(t, 5, maximum)
+	})
+
+	t.Run("SmallerBuffer", func(t *testing.T) {
+		runner := NewCVM(1_000, intTestComparator)
+		for _, element := range newTestIntStream(100_000, 10_000) {
+			runner.Process(element)
+		}
+		median, ok := runner.Quantile(0.5)
+		assert.True(t, ok)
+		assert.InDelta(t, 5_000, median, 500)
+	})
+}
+
 // Test cases from original paper found at https://cs.stanford.edu/~knuth/papers/cvm-note.pdf
 // Tests use different buffer size for each test stream. Every stream containing total of 1_000_000 elements.
 // NOTE: These tests can fail due to randomness in algorithm specially for smaller buffer sizes. Try to run them multiple time.
@@ -244,3 +872,37 @@ func TestPaperStreams(t *testing.T) {
 		})
 	})
 }
+
+// FuzzProcess runs random streams through CVM and checks buffer invariants after every processed element.
+// While sampling probability is 1 the estimate has to match exact number of distinct elements.
+func FuzzProcess(f *testing.F) {
+	f.Add(uint8(1), []byte{1, 2, 3, 1, 2, 3})
+	f.Add(uint8(4), []byte{5, 4, 3, 2, 1, 0, 1, 2, 3, 4, 5, 6, 7, 8, 9})
+	f.Add(uint8(255), []byte{0, 0, 0, 1})
+
+	f.Fuzz(func(t *testing.T, bufferSize uint8, stream []byte) {
+		if bufferSize == 0 {
+			t.Skip()
+		}
+		runner := NewCVM(int(bufferSize), intTestComparator)
+		distinct := make(map[int]bool)
+
+		for i, element := range stream {
+			n := runner.Process(int(element))
+			distinct[int(element)] = true
+
+			if err := runner.buffer.Validate(); err != nil {
+				t.Fatalf("element %d: %v", i, err)
+			}
+			if runner.buffer.Size > runner.bufferSize {
+				t.Fatalf("element %d: buffer size %d exceeds %d", i, runner.buffer.Size, runner.bufferSize)
+			}
+			if runner.buffer.Root != nil && runner.buffer.Root.Priority >= runner.p {
+				t.Fatalf("element %d: priority %f is not below p %f", i, runner.buffer.Root.Priority, runner.p)
+			}
+			if runner.p == 1.0 && n != len(distinct) {
+				t.Fatalf("element %d: estimate is %d, expected %d", i, n, len(distinct))
+			}
+		}
+	})
+}
@@ -0,0 +1,50 @@
+package cvm
+
+import (
+	"fmt"
+	"io"
+)
+
+// DumpFormat defines how Dump writes buffer contents.
+type DumpFormat int
+
+const (
+	// DumpInOrder writes one sampled element with its priority per line, in order defined by comparator.
+	DumpInOrder DumpFormat = iota
+	// DumpTree writes treap structure, one node per line indented by depth. Children are marked with L: and R:.
+	DumpTree
+	// DumpDOT writes treap structure as a Graphviz DOT digraph with values and priorities as node labels.
+	DumpDOT
+)
+
+// Dump writes whole buffer to writer in given format. Use it for debugging, to see which elements
+// the sample actually holds when an estimate looks off. Returns the first error returned by writer.
+func (cvm *CVM[T]) Dump(writer io.Writer, format DumpFormat) error {
+	ew := &errWriter{writer: writer}
+	switch format {
+	case DumpInOrder:
+		cvm.buffer.printInOrder(ew)
+	case DumpTree:
+		cvm.buffer.printTree(ew)
+	case DumpDOT:
+		cvm.buffer.printDOT(ew)
+	default:
+		return fmt.Errorf("cvm: unknown dump format %d", format)
+	}
+	return ew.err
+}
+
+// errWriter remembers the first write error, so printing functions don't have to check errors after every write.
+type errWriter struct {
+	writer io.Writer
+	err    error
+}
+
+func (ew *errWriter) Write(p []byte) (int, error) {
+	if ew.err != nil {
+		return 0, ew.err
+	}
+	n, err := ew.writer.Write(p)
+	ew.err = err
+	return n, err
+}
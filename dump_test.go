@@ -0,0 +1,49 @@
+package cvm
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+func TestDump(t *testing.T) {
+	runner := NewOrderedCVM[int](10)
+	for _, element := range []int{3, 1, 2} {
+		runner.Process(element)
+	}
+
+	t.Run("InOrder", func(t *testing.T) {
+		writerBuffer := new(bytes.Buffer)
+		assert.Nil(t, runner.Dump(writerBuffer, DumpInOrder))
+		assert.Equal(t, 3, bytes.Count(writerBuffer.Bytes(), []byte("\n")))
+		assert.Regexp(t, `^<Value: 1, .*\n<Value: 2, .*\n<Value: 3, `, writerBuffer.String())
+	})
+
+	t.Run("Tree", func(t *testing.T) {
+		writerBuffer := new(bytes.Buffer)
+		assert.Nil(t, runner.Dump(writerBuffer, DumpTree))
+		assert.Equal(t, 3, bytes.Count(writerBuffer.Bytes(), []byte("<Value: ")))
+	})
+
+	t.Run("DOT", func(t *testing.T) {
+		writerBuffer := new(bytes.Buffer)
+		assert.Nil(t, runner.Dump(writerBuffer, DumpDOT))
+		assert.Equal(t, 2, bytes.Count(writerBuffer.Bytes(), []byte(" -> ")))
+	})
+
+	t.Run("UnknownFormat", func(t *testing.T) {
+		assert.EqualError(t, runner.Dump(new(bytes.Buffer), DumpFormat(42)), "cvm: unknown dump format 42")
+	})
+
+	t.Run("WriterError", func(t *testing.T) {
+		assert.EqualError(t, runner.Dump(failingWriter{}, DumpTree), "disk full")
+	})
+}
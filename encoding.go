@@ -0,0 +1,123 @@
+package cvm
+
+import (
+	"bytes"
+	"encoding/gob"
+
+	"github.com/tentameneu/cvm-go/internal/treap"
+)
+
+const cvmEncodingVersion = 1
+
+// cvmState is a serializable snapshot of CVM. Hooks, comparator check and random source are not part of it.
+type cvmState[T any] struct {
+	Version    int
+	BufferSize int
+	Total      int
+	P          float64
+	Algorithm  Algorithm
+	Saturated  bool
+	Failed     bool
+	Values     []T
+	Priorities []float64
+	// MaxK is maxK of TrackOccurrences, Counts and Probabilities describe occurrences of sampled elements if it is not 0.
+	MaxK          int
+	Counts        []int
+	Probabilities [][]float64
+}
+
+// MarshalBinary encodes state of the sketch, including the whole sample, with encoding/gob.
+// Elements have to be encodable by gob, which for structs means having exported fields.
+func (cvm *CVM[T]) MarshalBinary() ([]byte, error) {
+	state := cvmState[T]{
+		Version:    cvmEncodingVersion,
+		BufferSize: cvm.bufferSize,
+		Total:      cvm.total,
+		P:          cvm.p,
+		Algorithm:  cvm.algorithm,
+		Saturated:  cvm.saturated,
+		Failed:     cvm.err != nil,
+		Values:     make([]T, 0, cvm.buffer.Size),
+		Priorities: make([]float64, 0, cvm.buffer.Size),
+		MaxK:       cvm.maxK,
+	}
+	cvm.buffer.Ascend(func(node *treap.Node[T, *occurrences]) bool {
+		state.Values = append(state.Values, node.Value)
+		state.Priorities = append(state.Priorities, node.Priority)
+		if cvm.maxK != 0 {
+			occurrences := occurrencesOrOnce(node)
+			state.Counts = append(state.Counts, occurrences.count)
+			state.Probabilities = append(state.Probabilities, occurrences.probabilities)
+		}
+		return true
+	})
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(state); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary replaces state of the sketch with data encoded by MarshalBinary. Comparator, sizeOf, hooks and random source are kept,
+// so create the sketch with the same comparator as the encoded one, for example: sketch := NewCVM(0, comparator); sketch.UnmarshalBinary(data).
+// Returns ErrIncompatible if data was encoded by a different version or doesn't describe a valid sketch,
+// like one with sampling probability outside of (0, 1] or with duplicate elements.
+func (cvm *CVM[T]) UnmarshalBinary(data []byte) error {
+	var state cvmState[T]
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&state); err != nil {
+		return err
+	}
+	if !state.valid(cvm.buffer.Compare) {
+		return ErrIncompatible
+	}
+
+	cvm.bufferSize = state.BufferSize
+	cvm.total = state.Total
+	cvm.p = state.P
+	cvm.algorithm = state.Algorithm
+	cvm.saturated = state.Saturated
+	cvm.maxK = state.MaxK
+	cvm.err = nil
+	if state.Failed {
+		cvm.err = ErrBufferFull
+	}
+	cvm.buffer = newTreapBuffer(cvm.buffer.Compare)
+	cvm.bytes = 0
+	for i, value := range state.Values {
+		var counted *occurrences
+		if state.MaxK != 0 {
+			counted = &occurrences{count: state.Counts[i], probabilities: state.Probabilities[i]}
+		}
+		cvm.insert(value, state.Priorities[i], counted)
+	}
+	return nil
+}
+
+// valid reports whether state describes a sketch MarshalBinary could encode: sampling probability is in (0, 1],
+// values are in strictly ascending order by compare, so there are no duplicates, every priority is below sampling
+// probability and occurrences, if tracked, are consistent with maxK.
+func (state *cvmState[T]) valid(compare func(x, y T) int) bool {
+	if state.Version != cvmEncodingVersion || !(state.P > 0 && state.P <= 1) ||
+		state.BufferSize < 0 || state.Total < 0 || state.MaxK < 0 || len(state.Values) != len(state.Priorities) {
+		return false
+	}
+	if state.MaxK != 0 && (len(state.Values) != len(state.Counts) || len(state.Values) != len(state.Probabilities)) {
+		return false
+	}
+	for i, value := range state.Values {
+		if i > 0 && compare(state.Values[i-1], value) >= 0 {
+			return false
+		}
+		if !(state.Priorities[i] >= 0 && state.Priorities[i] < state.P) {
+			return false
+		}
+		if state.MaxK != 0 {
+			count, probabilities := state.Counts[i], len(state.Probabilities[i])
+			if count < 1 || probabilities >= count || probabilities >= state.MaxK {
+				return false
+			}
+		}
+	}
+	return true
+}
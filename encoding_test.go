@@ -0,0 +1,112 @@
+package cvm
+
+import (
+	"bytes"
+	"encoding/gob"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testExportedStruct struct {
+	ID   int
+	Name string
+}
+
+func TestMarshalBinary(t *testing.T) {
+	t.Run("RoundTrip", func(t *testing.T) {
+		runner := NewCVM(100, intTestComparator)
+		for _, element := range newTestIntStream(10_000, 1_000) {
+			runner.Process(element)
+		}
+		data, err := runner.MarshalBinary()
+		assert.Nil(t, err)
+
+		decoded := NewCVM(0, intTestComparator)
+		assert.Nil(t, decoded.UnmarshalBinary(data))
+		assert.Nil(t, decoded.buffer.Validate())
+		assert.Equal(t, runner.Sample(), decoded.Sample())
+		assert.Equal(t, runner.p, decoded.p)
+		assert.Equal(t, runner.total, decoded.total)
+		assert.Equal(t, runner.bufferSize, decoded.bufferSize)
+		assert.Equal(t, runner.saturated, decoded.saturated)
+		assert.Equal(t, runner.N(), decoded.N())
+
+		for _, element := range newTestIntStream(10_000, 1_000) {
+			decoded.Process(element)
+		}
+		assert.LessOrEqual(t, decoded.buffer.Size, 100)
+	})
+
+	t.Run("Struct", func(t *testing.T) {
+		runner := NewCVMByKey(10, func(x testExportedStruct) int { return x.ID })
+		runner.Process(testExportedStruct{ID: 1, Name: "Bruce"})
+		data, err := runner.MarshalBinary()
+		assert.Nil(t, err)
+
+		decoded := NewCVMByKey(10, func(x testExportedStruct) int { return x.ID })
+		assert.Nil(t, decoded.UnmarshalBinary(data))
+		assert.Equal(t, []testExportedStruct{{ID: 1, Name: "Bruce"}}, decoded.Sample())
+	})
+
+	t.Run("UnexportedFields", func(t *testing.T) {
+		runner := NewCVM(10, structTestComparator)
+		runner.Process(&testStruct{id: 1, name: "Bruce"})
+		_, err := runner.MarshalBinary()
+		assert.Error(t, err)
+	})
+
+	t.Run("Failed", func(t *testing.T) {
+		runner := NewCVMWithAlgorithm(1, intTestComparator, Algorithm1)
+		for i := 0; runner.Err() == nil; i++ {
+			runner.Process(i)
+		}
+		data, err := runner.MarshalBinary()
+		assert.Nil(t, err)
+		decoded := NewCVM(0, intTestComparator)
+		assert.Nil(t, decoded.UnmarshalBinary(data))
+		assert.Equal(t, Algorithm1, decoded.algorithm)
+		assert.ErrorIs(t, decoded.Err(), ErrBufferFull)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		assert.Error(t, NewCVM(10, intTestComparator).UnmarshalBinary([]byte("garbage")))
+	})
+
+	t.Run("InvalidState", func(t *testing.T) {
+		valid := func() cvmState[int] {
+			return cvmState[int]{
+				Version: cvmEncodingVersion, BufferSize: 10, Total: 3, P: 0.5,
+				Values: []int{1, 2, 3}, Priorities: []float64{0.1, 0.2, 0.3},
+			}
+		}
+		for name, corrupt := range map[string]func(state *cvmState[int]){
+			"Valid":              func(state *cvmState[int]) {},
+			"ZeroP":              func(state *cvmState[int]) { state.P = 0 },
+			"PAboveOne":          func(state *cvmState[int]) { state.P = 1.5 },
+			"NaNP":               func(state *cvmState[int]) { state.P = math.NaN() },
+			"DuplicateValues":    func(state *cvmState[int]) { state.Values = []int{1, 2, 2} },
+			"UnorderedValues":    func(state *cvmState[int]) { state.Values = []int{3, 2, 1} },
+			"PriorityAboveP":     func(state *cvmState[int]) { state.Priorities[2] = 0.7 },
+			"NegativeBufferSize": func(state *cvmState[int]) { state.BufferSize = -1 },
+			"MissingCounts":      func(state *cvmState[int]) { state.MaxK = 2 },
+			"TooManyProbabilities": func(state *cvmState[int]) {
+				state.MaxK = 2
+				state.Counts = []int{1, 3, 1}
+				state.Probabilities = [][]float64{nil, {1, 0.5}, nil}
+			},
+		} {
+			state := valid()
+			corrupt(&state)
+			var buf bytes.Buffer
+			assert.Nil(t, gob.NewEncoder(&buf).Encode(state))
+			err := NewCVM(10, intTestComparator).UnmarshalBinary(buf.Bytes())
+			if name == "Valid" {
+				assert.Nil(t, err)
+			} else {
+				assert.ErrorIs(t, err, ErrIncompatible, name)
+			}
+		}
+	})
+}
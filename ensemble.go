@@ -0,0 +1,95 @@
+package cvm
+
+import (
+	"fmt"
+	"math/rand"
+	"slices"
+	"sync"
+)
+
+// An Ensemble runs several independent CVMs over the same stream and combines their estimates with median-of-means.
+// Members are split into groups. Estimates are averaged within each group and the median of group averages is the result.
+// Averaging lowers variance and the median protects against a group with an unlucky run, so the combined estimate
+// is tighter and more reliable than the estimate of a single CVM, especially for small buffer sizes.
+type Ensemble[T any] struct {
+	members   []*CVM[T]
+	groupSize int
+}
+
+// NewEnsemble returns new Ensemble struct with groups*groupSize members, each with buffer of maximum size defined with bufferSize.
+// Every member has its own random source seeded from math/rand, so members use independent random streams.
+// Groups and groupSize have to be positive.
+func NewEnsemble[T any](groups, groupSize, bufferSize int, comparator Comparator[T]) *Ensemble[T] {
+	if groups < 1 || groupSize < 1 {
+		panic(fmt.Sprintf("cvm: Ensemble needs at least 1 group of at least 1 member, got %d groups of %d", groups, groupSize))
+	}
+	members := make([]*CVM[T], groups*groupSize)
+	for i := range members {
+		members[i] = NewCVM(bufferSize, comparator)
+		members[i].SetRand(rand.New(rand.NewSource(rand.Int63())))
+	}
+	return &Ensemble[T]{
+		members:   members,
+		groupSize: groupSize,
+	}
+}
+
+// Process element from stream in every member. Returns current combined estimate of number of distinct elements.
+func (ensemble *Ensemble[T]) Process(value T) int {
+	for _, member := range ensemble.members {
+		member.Process(value)
+	}
+	return ensemble.N()
+}
+
+// ProcessParallel processes batch of elements from stream, with every member running in its own goroutine.
+// Returns combined estimate of number of distinct elements after processing the whole batch.
+func (ensemble *Ensemble[T]) ProcessParallel(values []T) int {
+	var wg sync.WaitGroup
+	for _, member := range ensemble.members {
+		wg.Add(1)
+		go func(member *CVM[T]) {
+			defer wg.Done()
+			for _, value := range values {
+				member.Process(value)
+			}
+		}(member)
+	}
+	wg.Wait()
+	return ensemble.N()
+}
+
+// N calculates median-of-means estimate of number of distinct elements from estimates of all members.
+func (ensemble *Ensemble[T]) N() int {
+	means := make([]float64, 0, len(ensemble.members)/ensemble.groupSize)
+	for group := range len(ensemble.members) / ensemble.groupSize {
+		sum := 0.0
+		for _, member := range ensemble.members[group*ensemble.groupSize : (group+1)*ensemble.groupSize] {
+			sum += member.Estimate()
+		}
+		means = append(means, sum/float64(ensemble.groupSize))
+	}
+	slices.Sort(means)
+
+	middle := len(means) / 2
+	if len(means)%2 == 1 {
+		return int(means[middle])
+	}
+	return int((means[middle-1] + means[middle]) / 2)
+}
+
+// Estimates returns current estimates of all members, in order of members.
+func (ensemble *Ensemble[T]) Estimates() []int {
+	estimates := make([]int, len(ensemble.members))
+	for i, member := range ensemble.members {
+		estimates[i] = member.N()
+	}
+	return estimates
+}
+
+// Spread returns the lowest and the highest estimate among members. Wide spread means estimates are not reliable yet
+// and a bigger buffer size or more members are needed.
+func (ensemble *Ensemble[T]) Spread() (lowest, highest int) {
+	estimates := ensemble.Estimates()
+	return slices.Min(estimates), slices.Max(estimates)
+}
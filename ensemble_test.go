@@ -0,0 +1,97 @@
+package cvm
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewEnsemble(t *testing.T) {
+	ensemble := NewEnsemble(3, 5, 10, intTestComparator)
+	assert.Len(t, ensemble.members, 15)
+	assert.Equal(t, 5, ensemble.groupSize)
+	for _, member := range ensemble.members {
+		assert.NotNil(t, member.random)
+		assert.Equal(t, 10, member.bufferSize)
+	}
+
+	assert.Panics(t, func() { NewEnsemble(0, 5, 10, intTestComparator) })
+	assert.Panics(t, func() { NewEnsemble(3, 0, 10, intTestComparator) })
+	assert.Panics(t, func() { NewEnsemble(-1, 5, 10, intTestComparator) })
+}
+
+func TestEnsembleProcess(t *testing.T) {
+	t.Run("ExactBuffer", func(t *testing.T) {
+		ensemble := NewEnsemble(3, 3, 1_000, intTestComparator)
+		var n int
+		for _, element := range newTestIntStream(10_000, 1_000) {
+			n = ensemble.Process(element)
+		}
+		assert.Exactly(t, 1_000, n)
+		assert.Equal(t, []int{1_000, 1_000, 1_000, 1_000, 1_000, 1_000, 1_000, 1_000, 1_000}, ensemble.Estimates())
+		lowest, highest := ensemble.Spread()
+		assert.Equal(t, 1_000, lowest)
+		assert.Equal(t, 1_000, highest)
+	})
+
+	t.Run("SmallerBuffer", func(t *testing.T) {
+		ensemble := NewEnsemble(5, 5, 10, intTestComparator)
+		for i, member := range ensemble.members {
+			member.SetRand(rand.New(rand.NewSource(int64(i + 1))))
+		}
+		var n int
+		for _, element := range newTestIntStream(100_000, 10_000) {
+			n = ensemble.Process(element)
+		}
+		assert.InDelta(t, 10_000, n, 3_500)
+		lowest, highest := ensemble.Spread()
+		assert.Less(t, lowest, highest)
+		assert.LessOrEqual(t, lowest, n)
+		assert.GreaterOrEqual(t, highest, n)
+	})
+
+	t.Run("IndependentMembers", func(t *testing.T) {
+		ensemble := NewEnsemble(1, 2, 10, intTestComparator)
+		for _, element := range newTestIntStream(10_000, 1_000) {
+			ensemble.Process(element)
+		}
+		assert.NotEqual(t, ensemble.members[0].Sample(), ensemble.members[1].Sample())
+	})
+}
+
+func TestEnsembleProcessParallel(t *testing.T) {
+	ensemble := NewEnsemble(3, 3, 100, intTestComparator)
+	stream := newTestIntStream(100_000, 10_000)
+	ensemble.ProcessParallel(stream[:50_000])
+	n := ensemble.ProcessParallel(stream[50_000:])
+	assert.InDelta(t, 10_000, n, 3_000)
+	for _, member := range ensemble.members {
+		assert.Equal(t, 100_000, member.total)
+		assert.Nil(t, member.buffer.Validate())
+	}
+}
+
+func TestEnsembleN(t *testing.T) {
+	newFixedEnsemble := func(groupSize int, sizes ...int) *Ensemble[int] {
+		ensemble := NewEnsemble(len(sizes)/groupSize, groupSize, 100, intTestComparator)
+		for i, size := range sizes {
+			for element := 0; element < size; element++ {
+				ensemble.members[i].Process(element)
+			}
+		}
+		return ensemble
+	}
+
+	t.Run("MedianOfOddGroups", func(t *testing.T) {
+		assert.Equal(t, 20, newFixedEnsemble(2, 10, 20, 90, 90, 15, 25).N())
+	})
+
+	t.Run("MedianOfEvenGroups", func(t *testing.T) {
+		assert.Equal(t, 32, newFixedEnsemble(2, 10, 20, 40, 60).N())
+	})
+
+	t.Run("Mean", func(t *testing.T) {
+		assert.Equal(t, 40, newFixedEnsemble(3, 10, 20, 90).N())
+	})
+}
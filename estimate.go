@@ -0,0 +1,31 @@
+package cvm
+
+import "math"
+
+// An Estimate is a value estimated from sampled elements in buffer together with its standard error.
+// While sampling probability is still 1 the estimate is exact and StdErr is 0.
+type Estimate struct {
+	// Value is the estimate scaled from sample by sampling probability.
+	Value float64
+	// StdErr is estimated standard deviation of Value.
+	StdErr float64
+	// Sampled is number of sampled elements the estimate is based on. Fewer sampled elements mean less reliable StdErr.
+	Sampled int
+}
+
+// Interval returns confidence interval around Value for given confidence level between 0 and 1 (e.g. 0.95),
+// using normal approximation.
+func (e Estimate) Interval(confidence float64) (lower, upper float64) {
+	z := math.Sqrt2 * math.Erfinv(confidence)
+	return e.Value - z*e.StdErr, e.Value + z*e.StdErr
+}
+
+// newCountEstimate returns estimate of number of distinct elements from k sampled elements.
+// Every distinct element is in the sample with probability p, so k is binomial and k/p has variance n(1-p)/p.
+func newCountEstimate(k int, p float64) Estimate {
+	return Estimate{
+		Value:   float64(k) / p,
+		StdErr:  math.Sqrt(float64(k)*(1-p)) / p,
+		Sampled: k,
+	}
+}
@@ -0,0 +1,145 @@
+package cvm
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInterval(t *testing.T) {
+	t.Run("Exact", func(t *testing.T) {
+		lower, upper := Estimate{Value: 100, StdErr: 0, Sampled: 100}.Interval(0.95)
+		assert.Equal(t, 100.0, lower)
+		assert.Equal(t, 100.0, upper)
+	})
+
+	t.Run("Normal", func(t *testing.T) {
+		lower, upper := Estimate{Value: 100, StdErr: 10, Sampled: 50}.Interval(0.95)
+		assert.InDelta(t, 80.4, lower, 0.01)
+		assert.InDelta(t, 119.6, upper, 0.01)
+	})
+}
+
+func TestNewCountEstimate(t *testing.T) {
+	t.Run("Exact", func(t *testing.T) {
+		assert.Equal(t, Estimate{Value: 10, StdErr: 0, Sampled: 10}, newCountEstimate(10, 1))
+	})
+
+	t.Run("Sampled", func(t *testing.T) {
+		estimate := newCountEstimate(100, 0.25)
+		assert.Equal(t, 400.0, estimate.Value)
+		assert.InDelta(t, math.Sqrt(75)/0.25, estimate.StdErr, 1e-9)
+		assert.Equal(t, 100, estimate.Sampled)
+	})
+}
+
+func TestNRange(t *testing.T) {
+	t.Run("ExactBuffer", func(t *testing.T) {
+		runner := NewCVM(1_000, intTestComparator)
+		for _, element := range newTestIntStream(10_000, 1_000) {
+			runner.Process(element)
+		}
+		assert.Equal(t, Estimate{Value: 101, StdErr: 0, Sampled: 101}, runner.NRange(100, 200))
+		assert.Equal(t, Estimate{Value: 1, StdErr: 0, Sampled: 1}, runner.NRange(100, 100))
+		assert.Equal(t, Estimate{Value: 0, StdErr: 0, Sampled: 0}, runner.NRange(200, 100))
+		assert.Equal(t, Estimate{Value: 1_000, StdErr: 0, Sampled: 1_000}, runner.NRange(-10, 10_000))
+	})
+
+	t.Run("SmallerBuffer", func(t *testing.T) {
+		runner := NewCVM(1_000, intTestComparator)
+		runner.SetRand(rand.New(rand.NewSource(1)))
+		for _, element := range newTestIntStream(100_000, 10_000) {
+			runner.Process(element)
+		}
+		estimate := runner.NRange(2_000, 5_999)
+		assert.InDelta(t, 4_000, estimate.Value, 800)
+		assert.Greater(t, estimate.StdErr, 0.0)
+		lower, upper := estimate.Interval(0.999)
+		assert.Less(t, lower, 4_000.0)
+		assert.Greater(t, upper, 4_000.0)
+	})
+}
+
+func TestNWhere(t *testing.T) {
+	t.Run("ExactBuffer", func(t *testing.T) {
+		runner := NewCVM(1_000, intTestComparator)
+		for _, element := range newTestIntStream(10_000, 1_000) {
+			runner.Process(element)
+		}
+		assert.Equal(t, Estimate{Value: 100, StdErr: 0, Sampled: 100}, runner.NWhere(func(x int) bool { return x%10 == 0 }))
+		assert.Equal(t, Estimate{Value: 0, StdErr: 0, Sampled: 0}, runner.NWhere(func(x int) bool { return x < 0 }))
+	})
+
+	t.Run("SmallerBuffer", func(t *testing.T) {
+		runner := NewCVM(1_000, intTestComparator)
+		for _, element := range newTestIntStream(100_000, 10_000) {
+			runner.Process(element)
+		}
+		all := runner.NWhere(func(x int) bool { return true })
+		even := runner.NWhere(func(x int) bool { return x%2 == 0 })
+		assert.Equal(t, float64(runner.N()), math.Floor(all.Value))
+		assert.InDelta(t, 5_000, even.Value, 1_000)
+		assert.Less(t, even.StdErr, all.StdErr)
+		assert.Greater(t, even.StdErr/even.Value, all.StdErr/all.Value)
+	})
+}
+
+func TestEstimateSum(t *testing.T) {
+	t.Run("Empty", func(t *testing.T) {
+		runner := NewCVM(10, intTestComparator)
+		assert.Equal(t, Estimate{Value: 0, StdErr: 0, Sampled: 0}, runner.EstimateSum(func(x int) float64 { return float64(x) }))
+	})
+
+	t.Run("ExactBuffer", func(t *testing.T) {
+		runner := NewCVM(1_000, intTestComparator)
+		for _, element := range newTestIntStream(10_000, 1_000) {
+			runner.Process(element)
+		}
+		assert.Equal(t, Estimate{Value: 499_500, StdErr: 0, Sampled: 1_000}, runner.EstimateSum(func(x int) float64 { return float64(x) }))
+	})
+
+	t.Run("SmallerBuffer", func(t *testing.T) {
+		runner := NewCVM(1_000, intTestComparator)
+		for _, element := range newTestIntStream(100_000, 10_000) {
+			runner.Process(element)
+		}
+		estimate := runner.EstimateSum(func(x int) float64 { return 1 })
+		assert.InDelta(t, 10_000, estimate.Value, 2_000)
+		assert.InDelta(t, float64(runner.N()), estimate.Value, 1)
+		assert.Greater(t, estimate.StdErr, 0.0)
+	})
+}
+
+func TestEstimateMean(t *testing.T) {
+	t.Run("Empty", func(t *testing.T) {
+		runner := NewCVM(10, intTestComparator)
+		estimate := runner.EstimateMean(func(x int) float64 { return float64(x) })
+		assert.True(t, math.IsNaN(estimate.Value))
+		assert.True(t, math.IsNaN(estimate.StdErr))
+		assert.Equal(t, 0, estimate.Sampled)
+	})
+
+	t.Run("ExactBuffer", func(t *testing.T) {
+		runner := NewCVM(1_000, intTestComparator)
+		for _, element := range newTestIntStream(10_000, 1_000) {
+			runner.Process(element)
+		}
+		assert.Equal(t, Estimate{Value: 499.5, StdErr: 0, Sampled: 1_000}, runner.EstimateMean(func(x int) float64 { return float64(x) }))
+	})
+
+	t.Run("SmallerBuffer", func(t *testing.T) {
+		runner := NewCVM(1_000, intTestComparator)
+		// Element 0 repeats much more often than others, but it is counted only once.
+		for i, element := range newTestIntStream(100_000, 10_000) {
+			if i%2 == 0 {
+				element = 0
+			}
+			runner.Process(element)
+		}
+		estimate := runner.EstimateMean(func(x int) float64 { return float64(x) })
+		assert.InDelta(t, 5_000, estimate.Value, 500)
+		assert.InDelta(t, 2_886/math.Sqrt(float64(estimate.Sampled))*math.Sqrt(1-runner.p), estimate.StdErr, 20)
+	})
+}
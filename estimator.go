@@ -0,0 +1,118 @@
+package cvm
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/gob"
+	"errors"
+	"hash/fnv"
+)
+
+// Estimator is a common interface of algorithms estimating number of distinct elements in a stream.
+// CVM, KMV, HyperLogLog and Exact implement it, so call sites can swap algorithms without rewriting.
+type Estimator[T any] interface {
+	// Add element from stream.
+	Add(value T)
+	// Estimate returns current estimated number of distinct elements.
+	Estimate() float64
+	// Merge adds elements seen by other estimator, as if both streams were processed by this estimator.
+	// Returns ErrIncompatible if other is a different algorithm or has different parameters.
+	Merge(other Estimator[T]) error
+	encoding.BinaryMarshaler
+	encoding.BinaryUnmarshaler
+}
+
+var (
+	_ Estimator[int] = (*CVM[int])(nil)
+	_ Estimator[int] = (*KMV[int])(nil)
+	_ Estimator[int] = (*HyperLogLog[int])(nil)
+	_ Estimator[int] = (*Exact[int])(nil)
+)
+
+// ErrIncompatible is returned when merging estimators of different algorithms or with different parameters,
+// or when decoding data encoded by a different estimator.
+var ErrIncompatible = errors.New("cvm: incompatible estimators")
+
+// HashUint64 returns well mixed 64-bit hash of x, using finalizer of SplitMix64. Use it as hash function
+// of KMV and HyperLogLog for integer elements, for example: func(x int) uint64 { return cvm.HashUint64(uint64(x)) }.
+func HashUint64(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
+// HashBytes returns 64-bit hash of b. It is stable across processes, so estimators using it can be merged and stored.
+func HashBytes(b []byte) uint64 {
+	h := fnv.New64a()
+	h.Write(b)
+	return HashUint64(h.Sum64())
+}
+
+// HashString returns 64-bit hash of s. It is stable across processes, so estimators using it can be merged and stored.
+func HashString(s string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(s))
+	return HashUint64(h.Sum64())
+}
+
+// An Exact counts distinct elements exactly by keeping all of them in a map. Memory grows with number of distinct elements,
+// so it is meant for tests and for small streams, where it can be used in place of an approximate Estimator.
+type Exact[T comparable] struct {
+	seen map[T]struct{}
+}
+
+// NewExact returns new empty Exact struct.
+func NewExact[T comparable]() *Exact[T] {
+	return &Exact[T]{seen: make(map[T]struct{})}
+}
+
+// Add element from stream.
+func (exact *Exact[T]) Add(value T) {
+	exact.seen[value] = struct{}{}
+}
+
+// Estimate returns exact number of distinct elements.
+func (exact *Exact[T]) Estimate() float64 {
+	return float64(len(exact.seen))
+}
+
+// Merge adds all elements seen by other, which has to be *Exact[T].
+func (exact *Exact[T]) Merge(other Estimator[T]) error {
+	o, ok := other.(*Exact[T])
+	if !ok {
+		return ErrIncompatible
+	}
+	for value := range o.seen {
+		exact.seen[value] = struct{}{}
+	}
+	return nil
+}
+
+// MarshalBinary encodes all seen elements with encoding/gob.
+func (exact *Exact[T]) MarshalBinary() ([]byte, error) {
+	values := make([]T, 0, len(exact.seen))
+	for value := range exact.seen {
+		values = append(values, value)
+	}
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(values); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary replaces seen elements with elements decoded from data encoded by MarshalBinary.
+func (exact *Exact[T]) UnmarshalBinary(data []byte) error {
+	var values []T
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&values); err != nil {
+		return err
+	}
+	exact.seen = make(map[T]struct{}, len(values))
+	for _, value := range values {
+		exact.seen[value] = struct{}{}
+	}
+	return nil
+}
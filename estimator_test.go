@@ -0,0 +1,89 @@
+package cvm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var intTestHash = func(x int) uint64 { return HashUint64(uint64(x)) }
+
+func TestHash(t *testing.T) {
+	t.Run("Uint64", func(t *testing.T) {
+		assert.Equal(t, HashUint64(42), HashUint64(42))
+		assert.NotEqual(t, HashUint64(42), HashUint64(43))
+		assert.NotEqual(t, uint64(0), HashUint64(1)>>60)
+	})
+
+	t.Run("String", func(t *testing.T) {
+		assert.Equal(t, HashString("Bruce"), HashString("Bruce"))
+		assert.NotEqual(t, HashString("Bruce"), HashString("Clark"))
+		assert.Equal(t, HashString("Bruce"), HashBytes([]byte("Bruce")))
+	})
+}
+
+func TestEstimators(t *testing.T) {
+	estimators := map[string]func() Estimator[int]{
+		"CVM":         func() Estimator[int] { return NewCVM(1_000, intTestComparator) },
+		"KMV":         func() Estimator[int] { return NewKMV(1_000, intTestHash) },
+		"HyperLogLog": func() Estimator[int] { return NewHyperLogLog(12, intTestHash) },
+		"Exact":       func() Estimator[int] { return NewExact[int]() },
+	}
+
+	for name, newEstimator := range estimators {
+		t.Run(name, func(t *testing.T) {
+			t.Run("Estimate", func(t *testing.T) {
+				estimator := newEstimator()
+				for _, element := range newTestIntStream(100_000, 10_000) {
+					estimator.Add(element)
+				}
+				assert.InDelta(t, 10_000, estimator.Estimate(), 1_000)
+			})
+
+			t.Run("MergeDisjoint", func(t *testing.T) {
+				first, second := newEstimator(), newEstimator()
+				for _, element := range newTestIntStream(100_000, 10_000) {
+					if element%2 == 0 {
+						first.Add(element)
+					} else {
+						second.Add(element)
+					}
+				}
+				assert.Nil(t, first.Merge(second))
+				assert.InDelta(t, 10_000, first.Estimate(), 1_000)
+			})
+
+			t.Run("MergeIncompatible", func(t *testing.T) {
+				assert.ErrorIs(t, newEstimator().Merge(NewKMV(7, intTestHash)), ErrIncompatible)
+			})
+
+			t.Run("Marshal", func(t *testing.T) {
+				estimator := newEstimator()
+				for _, element := range newTestIntStream(10_000, 5_000) {
+					estimator.Add(element)
+				}
+				data, err := estimator.MarshalBinary()
+				assert.Nil(t, err)
+				decoded := newEstimator()
+				assert.Nil(t, decoded.UnmarshalBinary(data))
+				assert.Equal(t, estimator.Estimate(), decoded.Estimate())
+			})
+		})
+	}
+}
+
+func TestExact(t *testing.T) {
+	exact := NewExact[string]()
+	for _, element := range []string{"Bruce", "Clark", "Bruce"} {
+		exact.Add(element)
+	}
+	assert.Equal(t, 2.0, exact.Estimate())
+
+	other := NewExact[string]()
+	other.Add("Clark")
+	other.Add("Diana")
+	assert.Nil(t, exact.Merge(other))
+	assert.Equal(t, 3.0, exact.Estimate())
+
+	assert.Error(t, exact.UnmarshalBinary([]byte("garbage")))
+}
@@ -0,0 +1,45 @@
+package cvm
+
+// hooks holds callbacks registered on CVM, called in order of registration.
+type hooks[T any] struct {
+	probabilityChange []func(previous, current float64)
+	evict             []func(value T)
+	saturate          []func()
+}
+
+func (h *hooks[T]) fireSaturate() {
+	for _, hook := range h.saturate {
+		hook()
+	}
+}
+
+// fireChange calls evict callbacks for every evicted element and then probability change callbacks.
+func (h *hooks[T]) fireChange(previous, current float64, evicted []T) {
+	for _, value := range evicted {
+		for _, hook := range h.evict {
+			hook(value)
+		}
+	}
+	for _, hook := range h.probabilityChange {
+		hook(previous, current)
+	}
+}
+
+// OnProbabilityChange registers callback called every time sampling probability decreases, with its previous and current value.
+// Lower probability means lower precision of estimates, so it can be used to log or emit metrics about sketch quality.
+func (cvm *CVM[T]) OnProbabilityChange(hook func(previous, current float64)) {
+	cvm.hooks.probabilityChange = append(cvm.hooks.probabilityChange, hook)
+}
+
+// OnEvict registers callback called with every element evicted from buffer to make room for a new element or because of Resize.
+// Evict callbacks are called before probability change callbacks for the same processed element.
+func (cvm *CVM[T]) OnEvict(hook func(value T)) {
+	cvm.hooks.evict = append(cvm.hooks.evict, hook)
+}
+
+// OnSaturate registers callback called once, when buffer is full for the first time (or shrunk by Resize below size of the sample)
+// and sampling probability is about to drop below 1.
+// It is called before the sketch is changed, so until it returns estimates are still exact. Use it for example to snapshot the sketch.
+func (cvm *CVM[T]) OnSaturate(hook func()) {
+	cvm.hooks.saturate = append(cvm.hooks.saturate, hook)
+}
@@ -0,0 +1,79 @@
+package cvm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHooks(t *testing.T) {
+	t.Run("NotFull", func(t *testing.T) {
+		runner := NewCVM(10, intTestComparator)
+		runner.OnSaturate(func() { t.Fatal("saturated") })
+		runner.OnEvict(func(value int) { t.Fatal("evicted") })
+		runner.OnProbabilityChange(func(previous, current float64) { t.Fatal("probability changed") })
+		for _, element := range newTestIntStream(100, 10) {
+			runner.Process(element)
+		}
+	})
+
+	t.Run("Saturate", func(t *testing.T) {
+		runner := NewCVM(10, intTestComparator)
+		saturated := 0
+		runner.OnSaturate(func() {
+			saturated++
+			assert.Equal(t, 1.0, runner.p)
+			assert.Equal(t, 10, runner.N())
+		})
+		for _, element := range newTestIntStream(1_000, 100) {
+			runner.Process(element)
+		}
+		assert.Equal(t, 1, saturated)
+	})
+
+	t.Run("EvictAndProbabilityChange", func(t *testing.T) {
+		runner := NewCVM(10, intTestComparator)
+		events := make([]string, 0)
+		evicted := make([]int, 0)
+		changes := 0
+		runner.OnEvict(func(value int) {
+			events = append(events, "evict")
+			evicted = append(evicted, value)
+		})
+		runner.OnProbabilityChange(func(previous, current float64) {
+			events = append(events, "probability")
+			assert.Less(t, current, previous)
+			assert.Equal(t, current, runner.p)
+			changes++
+		})
+
+		expectedEvents := make([]string, 0)
+		expectedEvicted := make([]int, 0)
+		expectedChanges := 0
+		for _, element := range newTestIntStream(1_000, 100) {
+			result := runner.ProcessDetailed(element)
+			for _, value := range result.Evicted {
+				expectedEvents = append(expectedEvents, "evict")
+				expectedEvicted = append(expectedEvicted, value)
+			}
+			if result.PChanged {
+				expectedEvents = append(expectedEvents, "probability")
+				expectedChanges++
+			}
+		}
+		assert.Equal(t, expectedEvents, events)
+		assert.Equal(t, expectedEvicted, evicted)
+		assert.Equal(t, expectedChanges, changes)
+		assert.Greater(t, changes, 0)
+	})
+
+	t.Run("Multiple", func(t *testing.T) {
+		runner := NewCVM(1, intTestComparator)
+		calls := make([]int, 0)
+		runner.OnSaturate(func() { calls = append(calls, 1) })
+		runner.OnSaturate(func() { calls = append(calls, 2) })
+		runner.Process(1)
+		runner.Process(2)
+		assert.Equal(t, []int{1, 2}, calls)
+	})
+}
@@ -0,0 +1,104 @@
+package cvm
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+)
+
+// A HyperLogLog estimates number of distinct elements from maximum number of leading zeros in hashes,
+// tracked in 2^precision registers of one byte. Relative standard error is about 1.04/sqrt(2^precision).
+// Sketches with the same precision and hash function can be merged exactly.
+type HyperLogLog[T any] struct {
+	registers []uint8
+	precision uint8
+	hash      func(T) uint64
+}
+
+const hyperLogLogEncodingVersion = 1
+
+// NewHyperLogLog returns new HyperLogLog struct with 2^precision registers, using hash to hash elements.
+// Precision has to be between 4 and 18. Hash has to be well mixed and stable, like HashUint64, HashString or HashBytes.
+func NewHyperLogLog[T any](precision uint8, hash func(T) uint64) *HyperLogLog[T] {
+	if precision < 4 || precision > 18 {
+		panic(fmt.Sprintf("cvm: HyperLogLog precision %d out of range [4, 18]", precision))
+	}
+	return &HyperLogLog[T]{
+		registers: make([]uint8, 1<<precision),
+		precision: precision,
+		hash:      hash,
+	}
+}
+
+// Add element from stream.
+func (hll *HyperLogLog[T]) Add(value T) {
+	h := hll.hash(value)
+	index := h >> (64 - hll.precision)
+	// Set the lowest bit after shifted hash, so rank is at most 64 - precision + 1.
+	rank := uint8(bits.LeadingZeros64(h<<hll.precision|1<<(hll.precision-1)) + 1)
+	if rank > hll.registers[index] {
+		hll.registers[index] = rank
+	}
+}
+
+// Estimate returns current estimated number of distinct elements, with linear counting used for small cardinalities.
+func (hll *HyperLogLog[T]) Estimate() float64 {
+	m := float64(len(hll.registers))
+	sum, zeros := 0.0, 0
+	for _, register := range hll.registers {
+		sum += math.Ldexp(1, -int(register))
+		if register == 0 {
+			zeros++
+		}
+	}
+
+	estimate := hyperLogLogAlpha(len(hll.registers)) * m * m / sum
+	if estimate <= 2.5*m && zeros > 0 {
+		return m * math.Log(m/float64(zeros))
+	}
+	return estimate
+}
+
+func hyperLogLogAlpha(m int) float64 {
+	switch m {
+	case 16:
+		return 0.673
+	case 32:
+		return 0.697
+	case 64:
+		return 0.709
+	default:
+		return 0.7213 / (1 + 1.079/float64(m))
+	}
+}
+
+// Merge adds elements seen by other, which has to be *HyperLogLog[T] with the same precision.
+// Result is the same as if both streams were added to this sketch.
+func (hll *HyperLogLog[T]) Merge(other Estimator[T]) error {
+	o, ok := other.(*HyperLogLog[T])
+	if !ok || o.precision != hll.precision {
+		return ErrIncompatible
+	}
+	for i, register := range o.registers {
+		hll.registers[i] = max(hll.registers[i], register)
+	}
+	return nil
+}
+
+// MarshalBinary encodes precision and registers.
+func (hll *HyperLogLog[T]) MarshalBinary() ([]byte, error) {
+	data := make([]byte, 0, 2+len(hll.registers))
+	data = append(data, hyperLogLogEncodingVersion, hll.precision)
+	return append(data, hll.registers...), nil
+}
+
+// UnmarshalBinary replaces state of sketch with data encoded by MarshalBinary.
+// Returns ErrIncompatible if data is not a valid HyperLogLog encoding.
+func (hll *HyperLogLog[T]) UnmarshalBinary(data []byte) error {
+	if len(data) < 2 || data[0] != hyperLogLogEncodingVersion || data[1] < 4 || data[1] > 18 || len(data)-2 != 1<<data[1] {
+		return ErrIncompatible
+	}
+	hll.precision = data[1]
+	hll.registers = append([]uint8(nil), data[2:]...)
+	return nil
+}
@@ -0,0 +1,54 @@
+package cvm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHyperLogLog(t *testing.T) {
+	t.Run("Small", func(t *testing.T) {
+		hll := NewHyperLogLog(14, intTestHash)
+		for _, element := range newTestIntStream(1_000, 100) {
+			hll.Add(element)
+		}
+		assert.InDelta(t, 100, hll.Estimate(), 3)
+	})
+
+	t.Run("Large", func(t *testing.T) {
+		hll := NewHyperLogLog(14, intTestHash)
+		for element := 0; element < 1_000_000; element++ {
+			hll.Add(element)
+		}
+		assert.InDelta(t, 1_000_000, hll.Estimate(), 30_000)
+	})
+
+	t.Run("MergeEqualsUnion", func(t *testing.T) {
+		whole, first, second := NewHyperLogLog(10, intTestHash), NewHyperLogLog(10, intTestHash), NewHyperLogLog(10, intTestHash)
+		for i, element := range newTestIntStream(10_000, 3_000) {
+			whole.Add(element)
+			if i < 6_000 {
+				first.Add(element)
+			} else {
+				second.Add(element)
+			}
+		}
+		assert.Nil(t, first.Merge(second))
+		assert.Equal(t, whole.registers, first.registers)
+	})
+
+	t.Run("MergeDifferentPrecision", func(t *testing.T) {
+		assert.ErrorIs(t, NewHyperLogLog(10, intTestHash).Merge(NewHyperLogLog(12, intTestHash)), ErrIncompatible)
+	})
+
+	t.Run("InvalidPrecision", func(t *testing.T) {
+		assert.Panics(t, func() { NewHyperLogLog(3, intTestHash) })
+		assert.Panics(t, func() { NewHyperLogLog(19, intTestHash) })
+	})
+
+	t.Run("UnmarshalInvalid", func(t *testing.T) {
+		hll := NewHyperLogLog(10, intTestHash)
+		assert.ErrorIs(t, hll.UnmarshalBinary(nil), ErrIncompatible)
+		assert.ErrorIs(t, hll.UnmarshalBinary([]byte{hyperLogLogEncodingVersion, 4, 0}), ErrIncompatible)
+	})
+}
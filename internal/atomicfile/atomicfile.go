@@ -0,0 +1,139 @@
+// Package atomicfile writes files with a checksum, so that after a crash a file holds either its old or its new content
+// and any damage is detected when it is read.
+package atomicfile
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// headerSize is size of the frame header: CRC-32 (Castagnoli) of the payload and length of the payload.
+const headerSize = 8
+
+// ErrCorrupt is returned by Read when file fails checksum verification.
+var ErrCorrupt = errors.New("atomicfile: corrupt file")
+
+var crcTable = crc32.MakeTable(crc32.Castagnoli)
+
+// tempPattern is pattern of names of temporary files, see os.CreateTemp.
+const tempPattern = ".tmp-*"
+
+// Write writes payload with its checksum to a temporary file in the directory of path, syncs it and renames it to path.
+// Directory is synced too, so the rename survives a crash. Directory has to exist.
+func Write(path string, payload []byte) error {
+	return WriteAll(map[string][]byte{path: payload})
+}
+
+// WriteAll writes every payload of files to its path like Write. All temporary files are synced before any of them
+// is renamed, and every directory is synced once after all renames, so writing many files together is much faster
+// than a Write per file. After a crash every file holds either its old or its new content, but some files may be
+// replaced and others not. If an error is returned, files renamed before it keep their new content.
+func WriteAll(files map[string][]byte) error {
+	temps := make(map[string]string, len(files))
+	defer func() {
+		for _, temp := range temps {
+			os.Remove(temp)
+		}
+	}()
+
+	for path, payload := range files {
+		temp, err := writeTemp(filepath.Dir(path), payload)
+		if err != nil {
+			return err
+		}
+		temps[path] = temp
+	}
+	dirs := make(map[string]bool)
+	for path, temp := range temps {
+		if err := os.Rename(temp, path); err != nil {
+			return err
+		}
+		dirs[filepath.Dir(path)] = true
+	}
+	for dir := range dirs {
+		if err := SyncDir(dir); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeTemp writes payload with its checksum to a new temporary file in dir and syncs it. Returns name of the file.
+func writeTemp(dir string, payload []byte) (string, error) {
+	file, err := os.CreateTemp(dir, tempPattern)
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := file.Write(encodeFrame(payload)); err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return "", err
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return "", err
+	}
+	if err := file.Close(); err != nil {
+		os.Remove(file.Name())
+		return "", err
+	}
+	return file.Name(), nil
+}
+
+// RemoveTemp removes temporary files left in dir and its subdirectories by writes interrupted by a crash.
+// It must not run while files under dir are written.
+func RemoveTemp(dir string) error {
+	return filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if matched, _ := filepath.Match(tempPattern, entry.Name()); matched && !entry.IsDir() {
+			return os.Remove(path)
+		}
+		return nil
+	})
+}
+
+// Read returns payload of file written by Write. Returns ErrCorrupt if checksum or length of the payload doesn't match.
+func Read(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return decodeFrame(data)
+}
+
+func encodeFrame(payload []byte) []byte {
+	frame := make([]byte, headerSize+len(payload))
+	binary.BigEndian.PutUint32(frame[0:4], crc32.Checksum(payload, crcTable))
+	binary.BigEndian.PutUint32(frame[4:8], uint32(len(payload)))
+	copy(frame[headerSize:], payload)
+	return frame
+}
+
+func decodeFrame(frame []byte) ([]byte, error) {
+	if len(frame) < headerSize {
+		return nil, ErrCorrupt
+	}
+	payload := frame[headerSize:]
+	if binary.BigEndian.Uint32(frame[4:8]) != uint32(len(payload)) || binary.BigEndian.Uint32(frame[0:4]) != crc32.Checksum(payload, crcTable) {
+		return nil, ErrCorrupt
+	}
+	return payload, nil
+}
+
+// SyncDir syncs dir, so that creation, rename or removal of its entries survives a crash.
+func SyncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
+}
@@ -0,0 +1,93 @@
+package atomicfile
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFrame(t *testing.T) {
+	t.Run("RoundTrip", func(t *testing.T) {
+		for _, payload := range [][]byte{{}, []byte("sketch"), make([]byte, 1_000)} {
+			frame := encodeFrame(payload)
+			assert.Len(t, frame, headerSize+len(payload))
+			decoded, err := decodeFrame(frame)
+			assert.Nil(t, err)
+			assert.Equal(t, payload, decoded)
+		}
+	})
+
+	t.Run("WrongLength", func(t *testing.T) {
+		frame := encodeFrame([]byte("sketch"))
+		binary.BigEndian.PutUint32(frame[4:8], 7)
+		_, err := decodeFrame(frame)
+		assert.ErrorIs(t, err, ErrCorrupt)
+
+		_, err = decodeFrame(encodeFrame([]byte("sketch"))[:headerSize+5])
+		assert.ErrorIs(t, err, ErrCorrupt)
+	})
+
+	t.Run("BadChecksum", func(t *testing.T) {
+		frame := encodeFrame([]byte("sketch"))
+		frame[headerSize] ^= 0xff
+		_, err := decodeFrame(frame)
+		assert.ErrorIs(t, err, ErrCorrupt)
+
+		frame = encodeFrame([]byte("sketch"))
+		frame[0] ^= 0xff
+		_, err = decodeFrame(frame)
+		assert.ErrorIs(t, err, ErrCorrupt)
+	})
+
+	t.Run("ShortHeader", func(t *testing.T) {
+		frame := encodeFrame(nil)
+		for size := 0; size < headerSize; size++ {
+			_, err := decodeFrame(frame[:size])
+			assert.ErrorIs(t, err, ErrCorrupt, "size = %d", size)
+		}
+	})
+}
+
+func TestWrite(t *testing.T) {
+	t.Run("ReadBack", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "sketch")
+		assert.Nil(t, Write(path, []byte("old")))
+		assert.Nil(t, Write(path, []byte("new")))
+		payload, err := Read(path)
+		assert.Nil(t, err)
+		assert.Equal(t, []byte("new"), payload)
+	})
+
+	t.Run("NoTempFile", func(t *testing.T) {
+		dir := t.TempDir()
+		assert.Nil(t, Write(filepath.Join(dir, "sketch"), []byte("sketch")))
+		entries, err := os.ReadDir(dir)
+		assert.Nil(t, err)
+		assert.Len(t, entries, 1)
+		assert.Equal(t, "sketch", entries[0].Name())
+	})
+
+	t.Run("MissingDir", func(t *testing.T) {
+		assert.NotNil(t, Write(filepath.Join(t.TempDir(), "missing", "sketch"), []byte("sketch")))
+	})
+
+	t.Run("Corrupt", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "sketch")
+		assert.Nil(t, Write(path, []byte("sketch")))
+		data, err := os.ReadFile(path)
+		assert.Nil(t, err)
+		data[len(data)-1] ^= 0xff
+		assert.Nil(t, os.WriteFile(path, data, 0o644))
+		_, err = Read(path)
+		assert.ErrorIs(t, err, ErrCorrupt)
+	})
+}
+
+func TestSyncDir(t *testing.T) {
+	dir := t.TempDir()
+	assert.Nil(t, SyncDir(dir))
+	assert.ErrorIs(t, SyncDir(filepath.Join(dir, "missing")), os.ErrNotExist)
+}
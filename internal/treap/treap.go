@@ -0,0 +1,371 @@
+// Package treap implements a treap: a binary search tree ordered by values and max-heap ordered by node priorities.
+// Nodes keep sizes of their subtrees, so rank and select take O(depth).
+//
+// It backs both the sample buffer of the CVM algorithm, where priorities are the sampling random numbers,
+// and orderedset.Set, where priorities are drawn at random.
+package treap
+
+import "fmt"
+
+// A Tree is a treap ordered by Compare, which returns 0 if x == y, a negative int if x < y and a positive int if x > y.
+// Every node carries Data of type D, which is not interpreted by the tree.
+type Tree[T, D any] struct {
+	Root *Node[T, D]
+	// Size is number of nodes in the tree.
+	Size    int
+	Compare func(x, y T) int
+}
+
+// A Node is a node of Tree.
+type Node[T, D any] struct {
+	Value    T
+	Priority float64
+	Left     *Node[T, D]
+	Right    *Node[T, D]
+	Data     D
+	// size is number of nodes in subtree rooted at this node, used for rank and select.
+	size int
+}
+
+// New returns new empty Tree ordered by compare.
+func New[T, D any](compare func(x, y T) int) *Tree[T, D] {
+	return &Tree[T, D]{
+		Root:    nil,
+		Size:    0,
+		Compare: compare,
+	}
+}
+
+// NewNode returns new node, not yet inserted in any tree.
+func NewNode[T, D any](value T, priority float64) *Node[T, D] {
+	return &Node[T, D]{
+		Value:    value,
+		Priority: priority,
+		Left:     nil,
+		Right:    nil,
+		size:     1,
+	}
+}
+
+// SubtreeSize returns number of nodes in subtree rooted at node, 0 for nil node.
+func (node *Node[T, D]) SubtreeSize() int {
+	if node == nil {
+		return 0
+	}
+	return node.size
+}
+
+func (node *Node[T, D]) updateSize() {
+	node.size = node.Left.SubtreeSize() + node.Right.SubtreeSize() + 1
+}
+
+func rightRotate[T, D any](node *Node[T, D]) *Node[T, D] {
+	pivot := node.Left
+	temp := pivot.Right
+	pivot.Right = node
+	node.Left = temp
+	node.updateSize()
+	pivot.updateSize()
+	return pivot
+}
+
+func leftRotate[T, D any](node *Node[T, D]) *Node[T, D] {
+	pivot := node.Right
+	temp := pivot.Left
+	pivot.Left = node
+	node.Right = temp
+	node.updateSize()
+	pivot.updateSize()
+	return pivot
+}
+
+// Insert adds newNode to the tree. Node with value equal to value of newNode is removed first.
+func (tree *Tree[T, D]) Insert(newNode *Node[T, D]) {
+	tree.Delete(newNode.Value)
+	tree.Root = insertNode(tree.Root, newNode, tree.Compare)
+	tree.Size++
+}
+
+func insertNode[T, D any](root, newNode *Node[T, D], comp func(x, y T) int) *Node[T, D] {
+	if root == nil {
+		return newNode
+	}
+
+	if comp(root.Value, newNode.Value) > 0 {
+		root.Left = insertNode(root.Left, newNode, comp)
+		root.updateSize()
+		if root.Priority < newNode.Priority {
+			return rightRotate(root)
+		}
+		return root
+	} else if comp(root.Value, newNode.Value) < 0 {
+		root.Right = insertNode(root.Right, newNode, comp)
+		root.updateSize()
+		if root.Priority < newNode.Priority {
+			return leftRotate(root)
+		}
+		return root
+	}
+
+	return root
+}
+
+// Delete removes node with value from the tree. Returns true if such node was found.
+func (tree *Tree[T, D]) Delete(value T) bool {
+	root, deleted := deleteNode(tree.Root, value, tree.Compare, false)
+	tree.Root = root
+	if deleted {
+		tree.Size--
+	}
+	return deleted
+}
+
+func deleteNode[T, D any](root *Node[T, D], value T, comp func(x, y T) int, found bool) (*Node[T, D], bool) {
+	if root == nil {
+		return root, found
+	}
+
+	switch {
+	case comp(value, root.Value) < 0:
+		root.Left, found = deleteNode(root.Left, value, comp, found)
+	case comp(value, root.Value) > 0:
+		root.Right, found = deleteNode(root.Right, value, comp, found)
+	case comp(value, root.Value) == 0:
+		switch {
+		case root.Left == nil:
+			root = root.Right
+		case root.Right == nil:
+			root = root.Left
+		default:
+			if root.Left.Priority < root.Right.Priority {
+				root = leftRotate(root)
+				root.Left, _ = deleteNode(root.Left, value, comp, found)
+			} else {
+				root = rightRotate(root)
+				root.Right, _ = deleteNode(root.Right, value, comp, found)
+			}
+		}
+		found = true
+	}
+
+	if root != nil {
+		root.updateSize()
+	}
+	return root, found
+}
+
+// Find returns node with value equal to value, or nil if there is no such node.
+func (tree *Tree[T, D]) Find(value T) *Node[T, D] {
+	current := tree.Root
+
+	for current != nil {
+		if tree.Compare(value, current.Value) == 0 {
+			return current
+		}
+
+		if tree.Compare(value, current.Value) < 0 {
+			current = current.Left
+		} else {
+			current = current.Right
+		}
+	}
+
+	return nil
+}
+
+// Contains reports whether there is node with value equal to value.
+func (tree *Tree[T, D]) Contains(value T) bool {
+	return tree.Find(value) != nil
+}
+
+// Min returns node with the smallest value, or nil if the tree is empty.
+func (tree *Tree[T, D]) Min() *Node[T, D] {
+	if tree.Root == nil {
+		return nil
+	}
+	current := tree.Root
+	for current.Left != nil {
+		current = current.Left
+	}
+	return current
+}
+
+// Max returns node with the largest value, or nil if the tree is empty.
+func (tree *Tree[T, D]) Max() *Node[T, D] {
+	if tree.Root == nil {
+		return nil
+	}
+	current := tree.Root
+	for current.Right != nil {
+		current = current.Right
+	}
+	return current
+}
+
+// Floor returns node with the largest value less than or equal to value, or nil if there is no such node.
+func (tree *Tree[T, D]) Floor(value T) *Node[T, D] {
+	var floor *Node[T, D]
+	current := tree.Root
+	for current != nil {
+		switch c := tree.Compare(value, current.Value); {
+		case c < 0:
+			current = current.Left
+		case c > 0:
+			floor = current
+			current = current.Right
+		default:
+			return current
+		}
+	}
+	return floor
+}
+
+// Ceiling returns node with the smallest value greater than or equal to value, or nil if there is no such node.
+func (tree *Tree[T, D]) Ceiling(value T) *Node[T, D] {
+	var ceiling *Node[T, D]
+	current := tree.Root
+	for current != nil {
+		switch c := tree.Compare(value, current.Value); {
+		case c < 0:
+			ceiling = current
+			current = current.Left
+		case c > 0:
+			current = current.Right
+		default:
+			return current
+		}
+	}
+	return ceiling
+}
+
+// Ascend calls yield for every node in ascending order of values, until yield returns false.
+func (tree *Tree[T, D]) Ascend(yield func(*Node[T, D]) bool) {
+	ascendFrom(tree.Root, yield)
+}
+
+func ascendFrom[T, D any](node *Node[T, D], yield func(*Node[T, D]) bool) bool {
+	if node == nil {
+		return true
+	}
+	return ascendFrom(node.Left, yield) && yield(node) && ascendFrom(node.Right, yield)
+}
+
+// Descend calls yield for every node in descending order of values, until yield returns false.
+func (tree *Tree[T, D]) Descend(yield func(*Node[T, D]) bool) {
+	descendFrom(tree.Root, yield)
+}
+
+func descendFrom[T, D any](node *Node[T, D], yield func(*Node[T, D]) bool) bool {
+	if node == nil {
+		return true
+	}
+	return descendFrom(node.Right, yield) && yield(node) && descendFrom(node.Left, yield)
+}
+
+// Range calls yield for every node with lo <= value <= hi in ascending order of values, until yield returns false.
+// Only subtrees overlapping the range are visited.
+func (tree *Tree[T, D]) Range(lo, hi T, yield func(*Node[T, D]) bool) {
+	scan(tree.Root, lo, hi, tree.Compare, yield)
+}
+
+func scan[T, D any](node *Node[T, D], lo, hi T, comp func(x, y T) int, yield func(*Node[T, D]) bool) bool {
+	if node == nil {
+		return true
+	}
+	aboveLo, belowHi := comp(node.Value, lo) >= 0, comp(node.Value, hi) <= 0
+	if aboveLo && !scan(node.Left, lo, hi, comp, yield) {
+		return false
+	}
+	if aboveLo && belowHi && !yield(node) {
+		return false
+	}
+	if belowHi {
+		return scan(node.Right, lo, hi, comp, yield)
+	}
+	return true
+}
+
+// Rank returns number of nodes with value strictly less than value.
+func (tree *Tree[T, D]) Rank(value T) int {
+	rank := 0
+	current := tree.Root
+
+	for current != nil {
+		if tree.Compare(value, current.Value) <= 0 {
+			current = current.Left
+		} else {
+			rank += current.Left.SubtreeSize() + 1
+			current = current.Right
+		}
+	}
+
+	return rank
+}
+
+// Select returns node with k-th smallest value, counting from 0. Returns nil if k is out of range.
+func (tree *Tree[T, D]) Select(k int) *Node[T, D] {
+	if k < 0 || k >= tree.Size {
+		return nil
+	}
+	current := tree.Root
+
+	for current != nil {
+		leftSize := current.Left.SubtreeSize()
+		switch {
+		case k < leftSize:
+			current = current.Left
+		case k > leftSize:
+			k -= leftSize + 1
+			current = current.Right
+		default:
+			return current
+		}
+	}
+
+	return nil
+}
+
+// Validate checks treap invariants: values are in binary search tree order, priorities are in max-heap order,
+// subtree sizes are correct and Size matches number of nodes. Returns error describing the first broken invariant.
+func (tree *Tree[T, D]) Validate() error {
+	count, err := validateNode(tree.Root, nil, nil, tree.Compare)
+	if err != nil {
+		return err
+	}
+	if count != tree.Size {
+		return fmt.Errorf("size mismatch: Size is %d, but treap has %d nodes", tree.Size, count)
+	}
+	return nil
+}
+
+func validateNode[T, D any](root, lower, upper *Node[T, D], comp func(x, y T) int) (int, error) {
+	if root == nil {
+		return 0, nil
+	}
+
+	if lower != nil && comp(lower.Value, root.Value) >= 0 {
+		return 0, fmt.Errorf("order violated: %v is in right subtree of %v", root.Value, lower.Value)
+	}
+	if upper != nil && comp(root.Value, upper.Value) >= 0 {
+		return 0, fmt.Errorf("order violated: %v is in left subtree of %v", root.Value, upper.Value)
+	}
+	for _, child := range []*Node[T, D]{root.Left, root.Right} {
+		if child != nil && child.Priority > root.Priority {
+			return 0, fmt.Errorf("heap violated: %v with priority %f is child of %v with priority %f",
+				child.Value, child.Priority, root.Value, root.Priority)
+		}
+	}
+
+	left, err := validateNode(root.Left, lower, root, comp)
+	if err != nil {
+		return 0, err
+	}
+	right, err := validateNode(root.Right, root, upper, comp)
+	if err != nil {
+		return 0, err
+	}
+	if root.size != left+right+1 {
+		return 0, fmt.Errorf("subtree size violated: %v has size %d, but its subtree has %d nodes", root.Value, root.size, left+right+1)
+	}
+	return left + right + 1, nil
+}
@@ -0,0 +1,153 @@
+package treap
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func intTestComparator(x, y int) int { return x - y }
+
+func newTestTree() *Tree[int, struct{}] {
+	return New[int, struct{}](intTestComparator)
+}
+
+func insertTestNode(tree *Tree[int, struct{}], value int, priority float64) {
+	tree.Insert(NewNode[int, struct{}](value, priority))
+}
+
+func TestValidate(t *testing.T) {
+	t.Run("Empty", func(t *testing.T) {
+		assert.Nil(t, newTestTree().Validate())
+	})
+
+	t.Run("Valid", func(t *testing.T) {
+		tree := newTestTree()
+		for i := 0; i < 1_000; i++ {
+			insertTestNode(tree, rand.Intn(100), rand.Float64())
+		}
+		assert.Nil(t, tree.Validate())
+	})
+
+	t.Run("Order", func(t *testing.T) {
+		tree := newTestTree()
+		insertTestNode(tree, 30, 0.200)
+		insertTestNode(tree, 20, 0.100)
+		tree.Root.Left.Value = 40
+		assert.EqualError(t, tree.Validate(), "order violated: 40 is in left subtree of 30")
+	})
+
+	t.Run("Heap", func(t *testing.T) {
+		tree := newTestTree()
+		insertTestNode(tree, 30, 0.200)
+		insertTestNode(tree, 40, 0.100)
+		tree.Root.Right.Priority = 0.300
+		assert.EqualError(t, tree.Validate(), "heap violated: 40 with priority 0.300000 is child of 30 with priority 0.200000")
+	})
+
+	t.Run("SubtreeSize", func(t *testing.T) {
+		tree := newTestTree()
+		insertTestNode(tree, 30, 0.200)
+		insertTestNode(tree, 40, 0.100)
+		tree.Root.Right.size++
+		assert.EqualError(t, tree.Validate(), "subtree size violated: 40 has size 2, but its subtree has 1 nodes")
+	})
+
+	t.Run("Size", func(t *testing.T) {
+		tree := newTestTree()
+		insertTestNode(tree, 30, 0.200)
+		tree.Size++
+		assert.EqualError(t, tree.Validate(), "size mismatch: Size is 2, but treap has 1 nodes")
+	})
+}
+
+func TestNavigation(t *testing.T) {
+	tree := newTestTree()
+	for _, value := range []int{30, 10, 50, 20, 40} {
+		insertTestNode(tree, value, rand.Float64())
+	}
+
+	t.Run("MinMax", func(t *testing.T) {
+		assert.Equal(t, 10, tree.Min().Value)
+		assert.Equal(t, 50, tree.Max().Value)
+		assert.Nil(t, newTestTree().Min())
+		assert.Nil(t, newTestTree().Max())
+	})
+
+	t.Run("FloorCeiling", func(t *testing.T) {
+		assert.Equal(t, 20, tree.Floor(25).Value)
+		assert.Equal(t, 30, tree.Floor(30).Value)
+		assert.Nil(t, tree.Floor(5))
+		assert.Equal(t, 30, tree.Ceiling(25).Value)
+		assert.Equal(t, 10, tree.Ceiling(10).Value)
+		assert.Nil(t, tree.Ceiling(55))
+	})
+
+	t.Run("Descend", func(t *testing.T) {
+		values := make([]int, 0)
+		tree.Descend(func(node *Node[int, struct{}]) bool {
+			values = append(values, node.Value)
+			return node.Value > 30
+		})
+		assert.Equal(t, []int{50, 40, 30}, values)
+	})
+
+	t.Run("Range", func(t *testing.T) {
+		values := make([]int, 0)
+		tree.Range(15, 45, func(node *Node[int, struct{}]) bool {
+			values = append(values, node.Value)
+			return true
+		})
+		assert.Equal(t, []int{20, 30, 40}, values)
+	})
+}
+
+// FuzzTree runs random insert and delete sequences against a map used as reference model.
+// Every pair of bytes is one operation: the first byte selects insert or delete and priority, the second one is the value.
+// Priorities are taken from a small set, so equal priorities are common.
+func FuzzTree(f *testing.F) {
+	f.Add([]byte{0, 30, 2, 40, 4, 20, 1, 30})
+	f.Add([]byte{0, 1, 0, 2, 0, 3, 0, 4, 1, 2, 1, 3})
+	f.Add([]byte{6, 10, 6, 20, 6, 30, 6, 5, 6, 25, 1, 20, 1, 10})
+
+	f.Fuzz(func(t *testing.T, operations []byte) {
+		tree := newTestTree()
+		model := make(map[int]bool)
+
+		for i := 0; i+1 < len(operations); i += 2 {
+			operation, value := operations[i], int(operations[i+1])
+			if operation%2 == 0 {
+				insertTestNode(tree, value, float64(operation%8)/8)
+				model[value] = true
+			} else {
+				if tree.Delete(value) != model[value] {
+					t.Fatalf("operation %d: Delete(%d) is %t, expected %t", i/2, value, !model[value], model[value])
+				}
+				delete(model, value)
+			}
+
+			if err := tree.Validate(); err != nil {
+				t.Fatalf("operation %d: %v", i/2, err)
+			}
+			if tree.Size != len(model) {
+				t.Fatalf("operation %d: size is %d, expected %d", i/2, tree.Size, len(model))
+			}
+			rank := 0
+			for v := 0; v < 256; v++ {
+				if tree.Contains(v) != model[v] {
+					t.Fatalf("operation %d: Contains(%d) is %t, expected %t", i/2, v, tree.Contains(v), model[v])
+				}
+				if tree.Rank(v) != rank {
+					t.Fatalf("operation %d: Rank(%d) is %d, expected %d", i/2, v, tree.Rank(v), rank)
+				}
+				if model[v] {
+					if node := tree.Select(rank); node == nil || node.Value != v {
+						t.Fatalf("operation %d: Select(%d) is not %d", i/2, rank, v)
+					}
+					rank++
+				}
+			}
+		}
+	})
+}
@@ -0,0 +1,123 @@
+package cvm
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+
+	"github.com/tentameneu/cvm-go/internal/treap"
+)
+
+// A KMV (K-Minimum-Values) estimates number of distinct elements from the k smallest hash values seen in stream.
+// Hashes are kept in the same treap as CVM sample, with normalized hash used as priority, so the largest kept hash is in root.
+// Relative standard error is about 1/sqrt(k-2). Sketches with the same k and hash function can be merged exactly.
+type KMV[T any] struct {
+	buffer *treapBuffer[uint64]
+	k      int
+	hash   func(T) uint64
+}
+
+const kmvEncodingVersion = 1
+
+// NewKMV returns new KMV struct keeping k smallest hashes of elements computed with hash.
+// K has to be at least 2. Hash has to be well mixed and stable, like HashUint64, HashString or HashBytes.
+func NewKMV[T any](k int, hash func(T) uint64) *KMV[T] {
+	if k < 2 {
+		panic(fmt.Sprintf("cvm: KMV k %d is below 2", k))
+	}
+	return &KMV[T]{
+		buffer: newTreapBuffer(CompareOrdered[uint64]),
+		k:      k,
+		hash:   hash,
+	}
+}
+
+// Add element from stream.
+func (kmv *KMV[T]) Add(value T) {
+	kmv.addHash(kmv.hash(value))
+}
+
+func (kmv *KMV[T]) addHash(h uint64) {
+	if kmv.buffer.Contains(h) {
+		return
+	}
+	if kmv.buffer.Size < kmv.k {
+		kmv.buffer.Insert(newNode(h, normalizeHash(h)))
+		return
+	}
+	if h < kmv.buffer.Root.Value {
+		kmv.buffer.Delete(kmv.buffer.Root.Value)
+		kmv.buffer.Insert(newNode(h, normalizeHash(h)))
+	}
+}
+
+func normalizeHash(h uint64) float64 {
+	return float64(h) / math.Exp2(64)
+}
+
+// Estimate returns current estimated number of distinct elements. Until k distinct hashes are seen, it is exact.
+func (kmv *KMV[T]) Estimate() float64 {
+	if kmv.buffer.Size < kmv.k {
+		return float64(kmv.buffer.Size)
+	}
+	return float64(kmv.k-1) / kmv.buffer.Root.Priority
+}
+
+// Merge adds hashes kept by other, which has to be *KMV[T] with the same k.
+// Result is the same as if both streams were added to this sketch.
+func (kmv *KMV[T]) Merge(other Estimator[T]) error {
+	o, ok := other.(*KMV[T])
+	if !ok || o.k != kmv.k {
+		return ErrIncompatible
+	}
+	o.buffer.Ascend(func(node *treap.Node[uint64, *occurrences]) bool {
+		kmv.addHash(node.Value)
+		return true
+	})
+	return nil
+}
+
+// MarshalBinary encodes k and kept hashes.
+func (kmv *KMV[T]) MarshalBinary() ([]byte, error) {
+	data := make([]byte, 0, 1+2*binary.MaxVarintLen64+8*kmv.buffer.Size)
+	data = append(data, kmvEncodingVersion)
+	data = binary.AppendUvarint(data, uint64(kmv.k))
+	data = binary.AppendUvarint(data, uint64(kmv.buffer.Size))
+	kmv.buffer.Ascend(func(node *treap.Node[uint64, *occurrences]) bool {
+		data = binary.BigEndian.AppendUint64(data, node.Value)
+		return true
+	})
+	return data, nil
+}
+
+// UnmarshalBinary replaces state of sketch with data encoded by MarshalBinary.
+// Returns ErrIncompatible if data is not a valid KMV encoding, like k below 2, more hashes than k or duplicate hashes.
+func (kmv *KMV[T]) UnmarshalBinary(data []byte) error {
+	if len(data) == 0 || data[0] != kmvEncodingVersion {
+		return ErrIncompatible
+	}
+	data = data[1:]
+	k, n := binary.Uvarint(data)
+	if n <= 0 || k < 2 || k > math.MaxInt {
+		return ErrIncompatible
+	}
+	data = data[n:]
+	size, n := binary.Uvarint(data)
+	if n <= 0 || size > k || uint64(len(data)-n) != 8*size {
+		return ErrIncompatible
+	}
+	data = data[n:]
+
+	for i := 1; i < int(size); i++ {
+		if binary.BigEndian.Uint64(data[8*(i-1):]) >= binary.BigEndian.Uint64(data[8*i:]) {
+			return ErrIncompatible
+		}
+	}
+
+	kmv.k = int(k)
+	kmv.buffer = newTreapBuffer(CompareOrdered[uint64])
+	for i := 0; i < int(size); i++ {
+		kmv.addHash(binary.BigEndian.Uint64(data[8*i:]))
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package cvm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKMV(t *testing.T) {
+	t.Run("FewerThanK", func(t *testing.T) {
+		kmv := NewKMV(1_000, intTestHash)
+		for _, element := range newTestIntStream(10_000, 500) {
+			kmv.Add(element)
+		}
+		assert.Equal(t, 500.0, kmv.Estimate())
+	})
+
+	t.Run("KeepsSmallestHashes", func(t *testing.T) {
+		kmv := NewKMV(100, intTestHash)
+		for _, element := range newTestIntStream(10_000, 1_000) {
+			kmv.Add(element)
+		}
+		assert.Nil(t, kmv.buffer.Validate())
+		assert.Equal(t, 100, kmv.buffer.Size)
+		largest := kmv.buffer.Root.Value
+		smaller := 0
+		for element := 0; element < 1_000; element++ {
+			if intTestHash(element) <= largest {
+				smaller++
+			}
+		}
+		assert.Equal(t, 100, smaller)
+	})
+
+	t.Run("MergeEqualsUnion", func(t *testing.T) {
+		whole, first, second := NewKMV(100, intTestHash), NewKMV(100, intTestHash), NewKMV(100, intTestHash)
+		for i, element := range newTestIntStream(10_000, 3_000) {
+			whole.Add(element)
+			if i < 6_000 {
+				first.Add(element)
+			} else {
+				second.Add(element)
+			}
+		}
+		assert.Nil(t, first.Merge(second))
+		assert.Equal(t, whole.Estimate(), first.Estimate())
+	})
+
+	t.Run("MergeDifferentK", func(t *testing.T) {
+		assert.ErrorIs(t, NewKMV(100, intTestHash).Merge(NewKMV(10, intTestHash)), ErrIncompatible)
+	})
+
+	t.Run("InvalidK", func(t *testing.T) {
+		assert.Panics(t, func() { NewKMV(1, intTestHash) })
+		assert.Panics(t, func() { NewKMV(0, intTestHash) })
+		assert.Panics(t, func() { NewKMV(-1, intTestHash) })
+	})
+
+	t.Run("UnmarshalInvalid", func(t *testing.T) {
+		kmv := NewKMV(100, intTestHash)
+		assert.ErrorIs(t, kmv.UnmarshalBinary(nil), ErrIncompatible)
+		assert.ErrorIs(t, kmv.UnmarshalBinary([]byte{kmvEncodingVersion, 1, 2}), ErrIncompatible)
+		assert.ErrorIs(t, kmv.UnmarshalBinary([]byte{kmvEncodingVersion, 1, 0}), ErrIncompatible)
+		assert.ErrorIs(t, kmv.UnmarshalBinary([]byte{kmvEncodingVersion, 0, 0}), ErrIncompatible)
+		overfull := []byte{kmvEncodingVersion, 2, 3, 0, 0, 0, 0, 0, 0, 0, 1, 0, 0, 0, 0, 0, 0, 0, 2, 0, 0, 0, 0, 0, 0, 0, 3}
+		assert.ErrorIs(t, kmv.UnmarshalBinary(overfull), ErrIncompatible)
+		duplicate := []byte{kmvEncodingVersion, 2, 2, 0, 0, 0, 0, 0, 0, 0, 1, 0, 0, 0, 0, 0, 0, 0, 1}
+		assert.ErrorIs(t, kmv.UnmarshalBinary(duplicate), ErrIncompatible)
+		assert.Equal(t, 100, kmv.k)
+	})
+}
@@ -0,0 +1,92 @@
+package cvm
+
+import (
+	"cmp"
+	"fmt"
+	"math/rand"
+	"slices"
+)
+
+// A Projection names one dimension of elements counted by Multi, like users or IP addresses of events.
+// Elements with Comparator returning 0 are the same distinct element of the projection.
+type Projection[T any] struct {
+	Name       string
+	Comparator Comparator[T]
+}
+
+// ProjectByKey returns Projection named name where elements are the same if they have the same key extracted with key.
+func ProjectByKey[T any, K cmp.Ordered](name string, key func(T) K) Projection[T] {
+	return Projection[T]{Name: name, Comparator: ByKey(key)}
+}
+
+// A Multi counts distinct elements of several projections of the same stream in one pass, with a CVM per projection.
+//
+// Only one random number is drawn for every processed element and it is shared by all projections.
+// CVM needs a fresh uniform random number for every element, independent of numbers drawn for other elements
+// of the same stream, which still holds for every projection, so every estimate is as accurate as with a separate CVM.
+// Estimates of different projections are correlated though, for example an event kept in sample of users
+// is more likely to be kept in sample of IP addresses too, so don't combine them as if they were independent.
+type Multi[T any] struct {
+	names    []string
+	sketches []*CVM[T]
+	random   *rand.Rand
+}
+
+// NewMulti returns new Multi struct with a CVM for every projection, each with buffer of maximum size defined with bufferSize.
+// Projections must have unique names.
+func NewMulti[T any](bufferSize int, projections ...Projection[T]) *Multi[T] {
+	for i, projection := range projections {
+		if slices.ContainsFunc(projections[:i], func(other Projection[T]) bool { return other.Name == projection.Name }) {
+			panic(fmt.Sprintf("cvm: Multi projection name %q is not unique", projection.Name))
+		}
+	}
+	multi := &Multi[T]{
+		names:    make([]string, len(projections)),
+		sketches: make([]*CVM[T], len(projections)),
+	}
+	for i, projection := range projections {
+		multi.names[i] = projection.Name
+		multi.sketches[i] = NewCVM(bufferSize, projection.Comparator)
+	}
+	return multi
+}
+
+// Process element from stream in sketch of every projection, drawing one random number for all of them.
+func (multi *Multi[T]) Process(value T) {
+	u := multi.draw()
+	for _, sketch := range multi.sketches {
+		sketch.process(value, u)
+	}
+}
+
+// SetRand sets source of random numbers used by Process. See CVM.SetRand.
+func (multi *Multi[T]) SetRand(random *rand.Rand) {
+	multi.random = random
+}
+
+func (multi *Multi[T]) draw() float64 {
+	if multi.random != nil {
+		return multi.random.Float64()
+	}
+	return rand.Float64()
+}
+
+// N returns current estimated number of distinct elements of every projection by its name.
+func (multi *Multi[T]) N() map[string]int {
+	estimates := make(map[string]int, len(multi.sketches))
+	for i, sketch := range multi.sketches {
+		estimates[multi.names[i]] = sketch.N()
+	}
+	return estimates
+}
+
+// Sketch returns CVM of projection named name, or nil if there is no such projection.
+// It can be used for other estimates of the projection, like Rank or NWhere, but it must not be processed directly.
+func (multi *Multi[T]) Sketch(name string) *CVM[T] {
+	for i, sketch := range multi.sketches {
+		if multi.names[i] == name {
+			return sketch
+		}
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package cvm
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testEvent struct {
+	user    int
+	ip      string
+	session int
+}
+
+func newTestMulti(bufferSize int) *Multi[testEvent] {
+	return NewMulti(bufferSize,
+		ProjectByKey("users", func(event testEvent) int { return event.user }),
+		ProjectByKey("ips", func(event testEvent) string { return event.ip }),
+		Projection[testEvent]{Name: "sessions", Comparator: ByKey(func(event testEvent) int { return event.session })},
+	)
+}
+
+func newTestEventStream(total int) []testEvent {
+	stream := make([]testEvent, total)
+	for i := range stream {
+		stream[i] = testEvent{user: i % 1_000, ip: string(rune('a' + i%20)), session: i % 5_000}
+	}
+	return stream
+}
+
+func TestMulti(t *testing.T) {
+	t.Run("ExactBuffer", func(t *testing.T) {
+		multi := newTestMulti(10_000)
+		for _, event := range newTestEventStream(100_000) {
+			multi.Process(event)
+		}
+		assert.Equal(t, map[string]int{"users": 1_000, "ips": 20, "sessions": 5_000}, multi.N())
+	})
+
+	t.Run("DuplicateName", func(t *testing.T) {
+		assert.Panics(t, func() {
+			NewMulti(10,
+				ProjectByKey("users", func(event testEvent) int { return event.user }),
+				ProjectByKey("users", func(event testEvent) int { return event.session }),
+			)
+		})
+	})
+
+	t.Run("SmallerBuffer", func(t *testing.T) {
+		multi := newTestMulti(500)
+		for _, event := range newTestEventStream(100_000) {
+			multi.Process(event)
+		}
+		n := multi.N()
+		assert.InDelta(t, 1_000, n["users"], 200)
+		assert.Equal(t, 20, n["ips"])
+		assert.InDelta(t, 5_000, n["sessions"], 1_000)
+	})
+
+	t.Run("Sketch", func(t *testing.T) {
+		multi := newTestMulti(10_000)
+		for _, event := range newTestEventStream(1_000) {
+			multi.Process(event)
+		}
+		assert.Equal(t, 20, multi.Sketch("ips").N())
+		assert.Equal(t, 1_000, multi.Sketch("sessions").total)
+		assert.Nil(t, multi.Sketch("missing"))
+	})
+
+	t.Run("SharedDraw", func(t *testing.T) {
+		multi := newTestMulti(100)
+		multi.Process(testEvent{user: 1, ip: "a", session: 1})
+		priority := multi.sketches[0].buffer.Root.Priority
+		for _, sketch := range multi.sketches {
+			assert.Equal(t, priority, sketch.buffer.Root.Priority)
+		}
+	})
+
+	t.Run("SetRand", func(t *testing.T) {
+		first, second := newTestMulti(100), newTestMulti(100)
+		first.SetRand(rand.New(rand.NewSource(1)))
+		second.SetRand(rand.New(rand.NewSource(1)))
+		for _, event := range newTestEventStream(10_000) {
+			first.Process(event)
+			second.Process(event)
+		}
+		assert.Equal(t, first.N(), second.N())
+	})
+}
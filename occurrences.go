@@ -0,0 +1,115 @@
+package cvm
+
+import (
+	"errors"
+	"math"
+	"slices"
+
+	"github.com/tentameneu/cvm-go/internal/treap"
+)
+
+// ErrOccurrencesNotTracked is returned by frequency estimates of CVM which doesn't track occurrences deep enough, see TrackOccurrences.
+var ErrOccurrencesNotTracked = errors.New("cvm: occurrences are not tracked")
+
+// occurrences counts occurrences of a sampled element since it was last added to the sample.
+type occurrences struct {
+	count int
+	// probabilities holds sampling probability just before each of the latest occurrences except the first one,
+	// at most maxK-1 of them, from the oldest to the newest.
+	probabilities []float64
+}
+
+// TrackOccurrences turns on counting of occurrences of sampled elements, which is needed by NAtLeast and FrequencyHistogram
+// for k up to maxK. Every sampled element keeps its count and up to maxK-1 sampling probabilities, and every Process
+// adds a lookup in buffer. Call it before processing elements, elements already sampled are counted as seen once.
+// Once elements were processed with occurrences tracked, maxK can't be changed and later calls are ignored,
+// as sampled elements don't hold probabilities needed by the new maxK.
+func (cvm *CVM[T]) TrackOccurrences(maxK int) {
+	if cvm.maxK != 0 && cvm.total > 0 {
+		return
+	}
+	cvm.maxK = max(maxK, 1)
+}
+
+// nextOccurrences returns occurrences of value including the one being processed, or nil if occurrences are not tracked.
+// Element of the sample occurs again with sampling probability before processing it, which is recorded for NAtLeast.
+func (cvm *CVM[T]) nextOccurrences(value T) *occurrences {
+	if cvm.maxK == 0 {
+		return nil
+	}
+	node := cvm.buffer.Find(value)
+	if node == nil {
+		return &occurrences{count: 1}
+	}
+	previous := occurrencesOrOnce(node)
+	probabilities := append(slices.Clone(previous.probabilities), cvm.p)
+	if len(probabilities) >= cvm.maxK {
+		probabilities = probabilities[len(probabilities)-cvm.maxK+1:]
+	}
+	return &occurrences{count: previous.count + 1, probabilities: probabilities}
+}
+
+// occurrencesOrOnce returns occurrences of sampled element, which was seen once if it was sampled before occurrences were tracked.
+func occurrencesOrOnce[T any](node *treap.Node[T, *occurrences]) *occurrences {
+	if node.Data == nil {
+		return &occurrences{count: 1}
+	}
+	return node.Data
+}
+
+// NAtLeast estimates number of distinct elements seen in stream at least k times, like distinct users with at least 3 visits.
+// Returns ErrOccurrencesNotTracked if TrackOccurrences was not called or k is above its maxK.
+//
+// Every occurrence of an element draws a new random number and element stays in the sample only while it is below
+// sampling probability, so element seen at least k times has its last k occurrences counted with probability equal to
+// current p times sampling probabilities just before each of its last k-1 occurrences, which is about p^k once p settles.
+// Sampled elements with count of at least k are weighted by inverse of that probability. While p is 1 the estimate is exact.
+// Precision drops quickly with k, as only about p^k of such elements are counted, which is reflected in StdErr.
+// Random numbers of an element slightly influence its own sampling probabilities, so the estimate is approximately unbiased.
+func (cvm *CVM[T]) NAtLeast(k int) (Estimate, error) {
+	if cvm.maxK == 0 || k > cvm.maxK {
+		return Estimate{}, ErrOccurrencesNotTracked
+	}
+	k = max(k, 1)
+	var estimate Estimate
+	variance := 0.0
+	cvm.buffer.Ascend(func(node *treap.Node[T, *occurrences]) bool {
+		occurrences := occurrencesOrOnce(node)
+		if occurrences.count < k {
+			return true
+		}
+		probability := cvm.p
+		latest := min(k-1, len(occurrences.probabilities))
+		for _, p := range occurrences.probabilities[len(occurrences.probabilities)-latest:] {
+			probability *= p
+		}
+		estimate.Value += 1 / probability
+		variance += (1 - probability) / (probability * probability)
+		estimate.Sampled++
+		return true
+	})
+	estimate.StdErr = math.Sqrt(variance)
+	return estimate, nil
+}
+
+// FrequencyHistogram estimates frequency distribution of distinct elements seen in stream. Value at index k estimates number
+// of distinct elements seen exactly k times, as NAtLeast(k) - NAtLeast(k+1), and value at index 0 is always 0.
+// The last value, at index maxK of TrackOccurrences, estimates number of distinct elements seen at least maxK times.
+// Values are approximate as described for NAtLeast, they can even be negative for large k with few sampled elements.
+// Returns ErrOccurrencesNotTracked if TrackOccurrences was not called.
+func (cvm *CVM[T]) FrequencyHistogram() ([]float64, error) {
+	if cvm.maxK == 0 {
+		return nil, ErrOccurrencesNotTracked
+	}
+	histogram := make([]float64, cvm.maxK+1)
+	next := 0.0
+	for k := cvm.maxK; k >= 1; k-- {
+		atLeast, err := cvm.NAtLeast(k)
+		if err != nil {
+			return nil, err
+		}
+		histogram[k] = atLeast.Value - next
+		next = atLeast.Value
+	}
+	return histogram, nil
+}
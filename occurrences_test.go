@@ -0,0 +1,160 @@
+package cvm
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tentameneu/cvm-go/internal/treap"
+)
+
+// newTestFrequencyStream returns shuffled stream of distinct elements, where element i is repeated i%maxCount+1 times.
+func newTestFrequencyStream(distinct, maxCount int) []int {
+	stream := make([]int, 0, distinct*(maxCount+1)/2)
+	for i := 0; i < distinct; i++ {
+		for j := 0; j <= i%maxCount; j++ {
+			stream = append(stream, i)
+		}
+	}
+	rand.Shuffle(len(stream), func(i, j int) { stream[i], stream[j] = stream[j], stream[i] })
+	return stream
+}
+
+func TestOccurrences(t *testing.T) {
+	t.Run("NotTracked", func(t *testing.T) {
+		runner := NewCVM(10, intTestComparator)
+		runner.Process(1)
+		_, err := runner.NAtLeast(1)
+		assert.ErrorIs(t, err, ErrOccurrencesNotTracked)
+		_, err = runner.FrequencyHistogram()
+		assert.ErrorIs(t, err, ErrOccurrencesNotTracked)
+	})
+
+	t.Run("ExactBuffer", func(t *testing.T) {
+		runner := NewCVM(1_000, intTestComparator)
+		runner.TrackOccurrences(4)
+		for _, element := range newTestFrequencyStream(1_000, 4) {
+			runner.Process(element)
+		}
+		estimate, err := runner.NAtLeast(3)
+		assert.Nil(t, err)
+		assert.Equal(t, Estimate{Value: 500, StdErr: 0, Sampled: 500}, estimate)
+		estimate, err = runner.NAtLeast(0)
+		assert.Nil(t, err)
+		assert.Equal(t, 1_000.0, estimate.Value)
+		_, err = runner.NAtLeast(5)
+		assert.ErrorIs(t, err, ErrOccurrencesNotTracked)
+
+		histogram, err := runner.FrequencyHistogram()
+		assert.Nil(t, err)
+		assert.Equal(t, []float64{0, 250, 250, 250, 250}, histogram)
+	})
+
+	t.Run("SmallerBuffer", func(t *testing.T) {
+		runner := NewCVM(5_000, intTestComparator)
+		runner.TrackOccurrences(4)
+		for _, element := range newTestFrequencyStream(20_000, 4) {
+			runner.Process(element)
+		}
+		assert.Less(t, runner.p, 1.0)
+		for k, expected := range []float64{20_000, 15_000, 10_000, 5_000} {
+			estimate, err := runner.NAtLeast(k + 1)
+			assert.Nil(t, err)
+			assert.InDelta(t, expected, estimate.Value, 4*estimate.StdErr, "k = %d", k+1)
+		}
+	})
+
+	t.Run("Algorithm1", func(t *testing.T) {
+		runner := NewCVMWithAlgorithm(1_000, intTestComparator, Algorithm1)
+		runner.TrackOccurrences(4)
+		for _, element := range newTestFrequencyStream(500, 4) {
+			runner.Process(element)
+		}
+		estimate, err := runner.NAtLeast(4)
+		assert.Nil(t, err)
+		assert.Equal(t, 125.0, estimate.Value)
+	})
+
+	t.Run("MaxK", func(t *testing.T) {
+		runner := NewCVM(1_000, intTestComparator)
+		runner.TrackOccurrences(2)
+		for _, element := range newTestFrequencyStream(100, 4) {
+			runner.Process(element)
+		}
+		runner.buffer.Ascend(func(node *treap.Node[int, *occurrences]) bool {
+			assert.LessOrEqual(t, len(node.Data.probabilities), 1)
+			return true
+		})
+		histogram, err := runner.FrequencyHistogram()
+		assert.Nil(t, err)
+		assert.Equal(t, []float64{0, 25, 75}, histogram)
+	})
+
+	t.Run("TrackedLate", func(t *testing.T) {
+		runner := NewCVM(1_000, intTestComparator)
+		runner.Process(1)
+		runner.Process(2)
+		runner.TrackOccurrences(2)
+		runner.Process(1)
+		estimate, err := runner.NAtLeast(1)
+		assert.Nil(t, err)
+		assert.Equal(t, 2.0, estimate.Value)
+		estimate, err = runner.NAtLeast(2)
+		assert.Nil(t, err)
+		assert.Equal(t, 1.0, estimate.Value)
+	})
+
+	t.Run("TrackedAgain", func(t *testing.T) {
+		runner := NewCVM(1_000, intTestComparator)
+		runner.TrackOccurrences(2)
+		for _, element := range []int{1, 1, 1, 1, 2} {
+			runner.Process(element)
+		}
+		runner.TrackOccurrences(4)
+		assert.Equal(t, 2, runner.maxK)
+		_, err := runner.NAtLeast(3)
+		assert.ErrorIs(t, err, ErrOccurrencesNotTracked)
+		estimate, err := runner.NAtLeast(2)
+		assert.Nil(t, err)
+		assert.Equal(t, 1.0, estimate.Value)
+
+		// Sampled elements with fewer probabilities than maxK needs, as decoded from inconsistent data, don't panic.
+		runner.maxK = 4
+		estimate, err = runner.NAtLeast(3)
+		assert.Nil(t, err)
+		assert.Equal(t, 1.0, estimate.Value)
+	})
+
+	t.Run("Marshal", func(t *testing.T) {
+		runner := NewCVM(1_000, intTestComparator)
+		runner.TrackOccurrences(4)
+		for _, element := range newTestFrequencyStream(100, 4) {
+			runner.Process(element)
+		}
+		data, err := runner.MarshalBinary()
+		assert.Nil(t, err)
+		decoded := NewCVM(0, intTestComparator)
+		assert.Nil(t, decoded.UnmarshalBinary(data))
+		histogram, err := decoded.FrequencyHistogram()
+		assert.Nil(t, err)
+		assert.Equal(t, []float64{0, 25, 25, 25, 25}, histogram)
+	})
+
+	t.Run("Merge", func(t *testing.T) {
+		first, second := NewCVM(1_000, intTestComparator), NewCVM(1_000, intTestComparator)
+		first.TrackOccurrences(2)
+		assert.ErrorIs(t, first.Merge(second), ErrIncompatible)
+		second.TrackOccurrences(3)
+		assert.ErrorIs(t, first.Merge(second), ErrIncompatible)
+
+		second.TrackOccurrences(2)
+		for _, element := range newTestFrequencyStream(100, 2) {
+			first.Process(element)
+			second.Process(element)
+		}
+		assert.Nil(t, first.Merge(second))
+		histogram, err := first.FrequencyHistogram()
+		assert.Nil(t, err)
+		assert.Equal(t, []float64{0, 50, 50}, histogram)
+	})
+}
@@ -0,0 +1,120 @@
+// Package orderedset provides a generic ordered set backed by a treap (randomized binary search tree).
+//
+// It is built on the same treap implementation and Comparator type as the sample buffer of the CVM algorithm
+// in the parent package. Node priorities are drawn at random, so expected depth is O(log n) for any insertion order.
+//
+// Elements are iterated with yield callbacks. Ascend and Descend have the signature of iter.Seq,
+// so with Go 1.23 or newer they can be ranged over: for v := range set.Ascend { ... }.
+package orderedset
+
+import (
+	"math/rand"
+
+	"github.com/tentameneu/cvm-go"
+	"github.com/tentameneu/cvm-go/internal/treap"
+)
+
+// A Set is an ordered set of elements. Elements are ordered and considered equal as defined by comparator.
+// The zero value is not usable, use New to create a Set. A Set is not safe for concurrent use.
+type Set[T any] struct {
+	tree *treap.Tree[T, struct{}]
+}
+
+// New returns new empty Set ordered by comparator.
+func New[T any](comparator cvm.Comparator[T]) *Set[T] {
+	return &Set[T]{
+		tree: treap.New[T, struct{}](comparator),
+	}
+}
+
+// Len returns number of elements in the set.
+func (s *Set[T]) Len() int {
+	return s.tree.Size
+}
+
+// Insert adds value to the set. If an equal element is already in the set, it is replaced with value.
+// Returns true if the set didn't contain an equal element.
+func (s *Set[T]) Insert(value T) bool {
+	if existing := s.tree.Find(value); existing != nil {
+		existing.Value = value
+		return false
+	}
+	s.tree.Insert(treap.NewNode[T, struct{}](value, rand.Float64()))
+	return true
+}
+
+// Delete removes element equal to value from the set. Returns true if such element was in the set.
+func (s *Set[T]) Delete(value T) bool {
+	return s.tree.Delete(value)
+}
+
+// Contains reports whether an element equal to value is in the set.
+func (s *Set[T]) Contains(value T) bool {
+	return s.tree.Contains(value)
+}
+
+// Get returns element from the set equal to value. Returns false if there is no such element.
+func (s *Set[T]) Get(value T) (T, bool) {
+	return valueOf(s.tree.Find(value))
+}
+
+// Min returns the smallest element in the set. Returns false if the set is empty.
+func (s *Set[T]) Min() (T, bool) {
+	return valueOf(s.tree.Min())
+}
+
+// Max returns the largest element in the set. Returns false if the set is empty.
+func (s *Set[T]) Max() (T, bool) {
+	return valueOf(s.tree.Max())
+}
+
+// Floor returns the largest element less than or equal to value. Returns false if there is no such element.
+func (s *Set[T]) Floor(value T) (T, bool) {
+	return valueOf(s.tree.Floor(value))
+}
+
+// Ceiling returns the smallest element greater than or equal to value. Returns false if there is no such element.
+func (s *Set[T]) Ceiling(value T) (T, bool) {
+	return valueOf(s.tree.Ceiling(value))
+}
+
+// valueOf returns value of node, or false if node is nil.
+func valueOf[T any](node *treap.Node[T, struct{}]) (T, bool) {
+	if node == nil {
+		var zero T
+		return zero, false
+	}
+	return node.Value, true
+}
+
+// Ascend calls yield for every element in ascending order, until yield returns false.
+func (s *Set[T]) Ascend(yield func(T) bool) {
+	s.tree.Ascend(func(node *treap.Node[T, struct{}]) bool {
+		return yield(node.Value)
+	})
+}
+
+// Descend calls yield for every element in descending order, until yield returns false.
+func (s *Set[T]) Descend(yield func(T) bool) {
+	s.tree.Descend(func(node *treap.Node[T, struct{}]) bool {
+		return yield(node.Value)
+	})
+}
+
+// Range calls yield for every element x with lo <= x <= hi in ascending order, until yield returns false.
+// Only subtrees overlapping the range are visited.
+func (s *Set[T]) Range(lo, hi T, yield func(T) bool) {
+	s.tree.Range(lo, hi, func(node *treap.Node[T, struct{}]) bool {
+		return yield(node.Value)
+	})
+}
+
+// Values returns all elements in ascending order.
+func (s *Set[T]) Values() []T {
+	values := make([]T, 0, s.tree.Size)
+	s.Ascend(func(value T) bool {
+		values = append(values, value)
+		return true
+	})
+	return values
+}
@@ -0,0 +1,198 @@
+package orderedset
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tentameneu/cvm-go"
+)
+
+type testPerson struct {
+	id   int
+	name string
+}
+
+func newTestSet(values ...int) *Set[int] {
+	set := New(cvm.CompareOrdered[int])
+	for _, value := range values {
+		set.Insert(value)
+	}
+	return set
+}
+
+func collect[T any](seq func(yield func(T) bool)) []T {
+	values := make([]T, 0)
+	seq(func(value T) bool {
+		values = append(values, value)
+		return true
+	})
+	return values
+}
+
+func collectRange(set *Set[int], lo, hi int) []int {
+	return collect(func(yield func(int) bool) { set.Range(lo, hi, yield) })
+}
+
+func TestNew(t *testing.T) {
+	set := New(cvm.CompareOrdered[int])
+	assert.Equal(t, 0, set.Len())
+	assert.Nil(t, set.tree.Root)
+}
+
+func TestInsert(t *testing.T) {
+	t.Run("New", func(t *testing.T) {
+		set := newTestSet()
+		assert.True(t, set.Insert(30))
+		assert.True(t, set.Insert(10))
+		assert.Equal(t, 2, set.Len())
+	})
+
+	t.Run("Existing", func(t *testing.T) {
+		set := newTestSet(30, 10)
+		assert.False(t, set.Insert(30))
+		assert.Equal(t, 2, set.Len())
+	})
+
+	t.Run("ReplacesEqual", func(t *testing.T) {
+		set := New(cvm.ByKey(func(x testPerson) int { return x.id }))
+		set.Insert(testPerson{id: 1, name: "Bruce"})
+		assert.False(t, set.Insert(testPerson{id: 1, name: "Batman"}))
+		value, ok := set.Get(testPerson{id: 1})
+		assert.True(t, ok)
+		assert.Equal(t, "Batman", value.name)
+	})
+}
+
+func TestDelete(t *testing.T) {
+	set := newTestSet(30, 10, 20, 40)
+
+	t.Run("Existing", func(t *testing.T) {
+		assert.True(t, set.Delete(20))
+		assert.Equal(t, 3, set.Len())
+		assert.False(t, set.Contains(20))
+	})
+
+	t.Run("NotExisting", func(t *testing.T) {
+		assert.False(t, set.Delete(20))
+		assert.Equal(t, 3, set.Len())
+	})
+
+	t.Run("All", func(t *testing.T) {
+		assert.True(t, set.Delete(10))
+		assert.True(t, set.Delete(30))
+		assert.True(t, set.Delete(40))
+		assert.Equal(t, 0, set.Len())
+		assert.Nil(t, set.tree.Root)
+	})
+}
+
+func TestContains(t *testing.T) {
+	set := newTestSet(30, 10, 20)
+	assert.True(t, set.Contains(10))
+	assert.True(t, set.Contains(30))
+	assert.False(t, set.Contains(15))
+}
+
+func TestMinMax(t *testing.T) {
+	t.Run("Empty", func(t *testing.T) {
+		set := newTestSet()
+		_, ok := set.Min()
+		assert.False(t, ok)
+		_, ok = set.Max()
+		assert.False(t, ok)
+	})
+
+	t.Run("NotEmpty", func(t *testing.T) {
+		set := newTestSet(30, 10, 20, 40)
+		value, ok := set.Min()
+		assert.True(t, ok)
+		assert.Equal(t, 10, value)
+		value, ok = set.Max()
+		assert.True(t, ok)
+		assert.Equal(t, 40, value)
+	})
+}
+
+func TestFloorCeiling(t *testing.T) {
+	set := newTestSet(30, 10, 20, 40)
+
+	t.Run("Floor", func(t *testing.T) {
+		value, ok := set.Floor(25)
+		assert.True(t, ok)
+		assert.Equal(t, 20, value)
+		value, ok = set.Floor(30)
+		assert.True(t, ok)
+		assert.Equal(t, 30, value)
+		_, ok = set.Floor(5)
+		assert.False(t, ok)
+	})
+
+	t.Run("Ceiling", func(t *testing.T) {
+		value, ok := set.Ceiling(25)
+		assert.True(t, ok)
+		assert.Equal(t, 30, value)
+		value, ok = set.Ceiling(10)
+		assert.True(t, ok)
+		assert.Equal(t, 10, value)
+		_, ok = set.Ceiling(45)
+		assert.False(t, ok)
+	})
+}
+
+func TestIteration(t *testing.T) {
+	set := newTestSet(30, 10, 50, 20, 40)
+
+	t.Run("Ascend", func(t *testing.T) {
+		assert.Equal(t, []int{10, 20, 30, 40, 50}, collect(set.Ascend))
+	})
+
+	t.Run("Descend", func(t *testing.T) {
+		assert.Equal(t, []int{50, 40, 30, 20, 10}, collect(set.Descend))
+	})
+
+	t.Run("Stop", func(t *testing.T) {
+		values := make([]int, 0)
+		set.Ascend(func(value int) bool {
+			values = append(values, value)
+			return value < 30
+		})
+		assert.Equal(t, []int{10, 20, 30}, values)
+	})
+
+	t.Run("Range", func(t *testing.T) {
+		assert.Equal(t, []int{20, 30, 40}, collectRange(set, 20, 40))
+		assert.Equal(t, []int{20, 30}, collectRange(set, 15, 35))
+		assert.Equal(t, []int{}, collectRange(set, 41, 49))
+		assert.Equal(t, []int{}, collectRange(set, 40, 20))
+	})
+
+	t.Run("Values", func(t *testing.T) {
+		assert.Equal(t, []int{10, 20, 30, 40, 50}, set.Values())
+	})
+}
+
+func TestRandomOperations(t *testing.T) {
+	set := New(cvm.CompareOrdered[int])
+	model := make(map[int]bool)
+	for i := 0; i < 10_000; i++ {
+		value := rand.Intn(500)
+		if rand.Intn(3) == 0 {
+			assert.Equal(t, model[value], set.Delete(value))
+			delete(model, value)
+		} else {
+			assert.Equal(t, !model[value], set.Insert(value))
+			model[value] = true
+		}
+	}
+
+	expected := make([]int, 0, len(model))
+	for value := range model {
+		expected = append(expected, value)
+	}
+	slices.Sort(expected)
+	assert.Nil(t, set.tree.Validate())
+	assert.Equal(t, len(model), set.Len())
+	assert.Equal(t, expected, set.Values())
+}
@@ -0,0 +1,226 @@
+package cvm
+
+import (
+	"container/list"
+	"math/rand"
+	"sync"
+)
+
+// EvictionPolicy selects which key Registry evicts when sketches together sample more than the registry cap.
+type EvictionPolicy int
+
+const (
+	// EvictLRU evicts the key which was processed least recently.
+	EvictLRU EvictionPolicy = iota
+	// EvictLeastValuable evicts the key with the lowest estimate among a few keys drawn uniformly at random.
+	// Keys with few distinct elements are cheap to rebuild and least interesting, so they go first.
+	EvictLeastValuable
+)
+
+// leastValuableCandidates is number of keys compared by EvictLeastValuable.
+const leastValuableCandidates = 5
+
+// A Registry keeps a CVM per key, for distinct counting grouped by key, like distinct users per page or per tenant.
+// Sketches are created lazily when a key is processed for the first time. Total number of sampled elements
+// across all sketches is capped, and when the cap is exceeded whole sketches are evicted by EvictionPolicy.
+// The cap counts sampled elements, not memory: empty sketches cost nothing unless SetSketchCost is used.
+// Registry is safe for concurrent use.
+type Registry[K comparable, T any] struct {
+	mutex     sync.Mutex
+	newSketch func(key K) *CVM[T]
+	entries   map[K]*list.Element
+	recency   *list.List
+	// keys holds every key at index of its entry, so EvictLeastValuable can draw keys at random.
+	keys       []K
+	maxSampled int
+	sampled    int
+	sketchCost int
+	policy     EvictionPolicy
+	onEvict    []func(key K, sketch *CVM[T])
+}
+
+type registryEntry[K comparable, T any] struct {
+	key    K
+	sketch *CVM[T]
+	// index is position of key in keys of the registry.
+	index int
+}
+
+// NewRegistry returns new Registry struct holding at most maxSampled sampled elements across all sketches.
+// Sketch for a new key is created with newSketch, which can size buffers per key. For sketches with byte budget
+// (see NewCVMWithByteBudget) their sizes in bytes are counted instead of number of elements.
+func NewRegistry[K comparable, T any](maxSampled int, policy EvictionPolicy, newSketch func(key K) *CVM[T]) *Registry[K, T] {
+	return &Registry[K, T]{
+		newSketch:  newSketch,
+		entries:    make(map[K]*list.Element),
+		recency:    list.New(),
+		maxSampled: maxSampled,
+		sampled:    0,
+		sketchCost: 0,
+		policy:     policy,
+	}
+}
+
+// Process element from stream of key, creating sketch for key if needed. Returns current estimated number of
+// distinct elements for key. Other keys may be evicted if the registry cap is exceeded, but never key itself.
+func (registry *Registry[K, T]) Process(key K, value T) int {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+
+	element, ok := registry.entries[key]
+	if ok {
+		registry.recency.MoveToFront(element)
+	} else {
+		element = registry.recency.PushFront(&registryEntry[K, T]{key: key, sketch: registry.newSketch(key), index: len(registry.keys)})
+		registry.entries[key] = element
+		registry.keys = append(registry.keys, key)
+	}
+	sketch := element.Value.(*registryEntry[K, T]).sketch
+	used := sketch.used()
+	n := sketch.Process(value)
+	registry.sampled += sketch.used() - used
+
+	for registry.overCap() && len(registry.entries) > 1 {
+		registry.evict(registry.victim(key))
+	}
+	return n
+}
+
+// overCap reports whether sampled elements together with fixed cost of every sketch exceed the registry cap.
+func (registry *Registry[K, T]) overCap() bool {
+	return registry.sampled+registry.sketchCost*len(registry.entries) > registry.maxSampled
+}
+
+// victim returns element of key to evict by registry policy, other than protected key.
+func (registry *Registry[K, T]) victim(protected K) *list.Element {
+	if registry.policy == EvictLRU {
+		victim := registry.recency.Back()
+		if victim.Value.(*registryEntry[K, T]).key == protected {
+			victim = victim.Prev()
+		}
+		return victim
+	}
+
+	// Candidates are distinct random indexes of keys other than protected, drawn with Floyd's algorithm.
+	// Index of protected key is replaced by the last index, which is never drawn.
+	last := len(registry.keys) - 1
+	protectedIndex := registry.entries[protected].Value.(*registryEntry[K, T]).index
+	drawn := make(map[int]bool, leastValuableCandidates)
+	var victim *list.Element
+	for j := last - min(leastValuableCandidates, last); j < last; j++ {
+		i := rand.Intn(j + 1)
+		if drawn[i] {
+			i = j
+		}
+		drawn[i] = true
+		if i == protectedIndex {
+			i = last
+		}
+		element := registry.entries[registry.keys[i]]
+		if victim == nil || element.Value.(*registryEntry[K, T]).sketch.Estimate() < victim.Value.(*registryEntry[K, T]).sketch.Estimate() {
+			victim = element
+		}
+	}
+	return victim
+}
+
+func (registry *Registry[K, T]) evict(element *list.Element) {
+	entry := registry.remove(element)
+	for _, hook := range registry.onEvict {
+		hook(entry.key, entry.sketch)
+	}
+}
+
+func (registry *Registry[K, T]) remove(element *list.Element) *registryEntry[K, T] {
+	entry := registry.recency.Remove(element).(*registryEntry[K, T])
+	delete(registry.entries, entry.key)
+	last := registry.keys[len(registry.keys)-1]
+	registry.keys[entry.index] = last
+	if last != entry.key {
+		registry.entries[last].Value.(*registryEntry[K, T]).index = entry.index
+	}
+	registry.keys = registry.keys[:len(registry.keys)-1]
+	registry.sampled -= entry.sketch.used()
+	return entry
+}
+
+// Estimate returns current estimated number of distinct elements for key.
+// Returns false if key was never processed or its sketch was evicted or deleted.
+func (registry *Registry[K, T]) Estimate(key K) (float64, bool) {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+
+	element, ok := registry.entries[key]
+	if !ok {
+		return 0, false
+	}
+	return element.Value.(*registryEntry[K, T]).sketch.Estimate(), true
+}
+
+// Estimates calls yield with every key in the registry and its current estimated number of distinct elements,
+// from the most to the least recently processed key, until yield returns false.
+// Its signature matches iter.Seq2.
+// Registry is locked during iteration, so yield must not call methods of the registry.
+func (registry *Registry[K, T]) Estimates(yield func(key K, n float64) bool) {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+
+	for element := registry.recency.Front(); element != nil; element = element.Next() {
+		entry := element.Value.(*registryEntry[K, T])
+		if !yield(entry.key, entry.sketch.Estimate()) {
+			return
+		}
+	}
+}
+
+// Delete removes sketch of key from the registry. Returns false if there was no sketch for key.
+// Evict callbacks are not called for deleted keys.
+func (registry *Registry[K, T]) Delete(key K) bool {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+
+	element, ok := registry.entries[key]
+	if !ok {
+		return false
+	}
+	registry.remove(element)
+	return true
+}
+
+// Len returns number of keys with a sketch in the registry.
+func (registry *Registry[K, T]) Len() int {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+
+	return len(registry.entries)
+}
+
+// Sampled returns total number of sampled elements (or their size in bytes) across all sketches in the registry.
+// Fixed cost of sketches set with SetSketchCost is not included.
+func (registry *Registry[K, T]) Sampled() int {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+
+	return registry.sampled
+}
+
+// SetSketchCost sets fixed cost of every sketch, which is counted toward the registry cap in addition to its sampled
+// elements, like memory of an empty sketch in bytes for sketches with byte budget (a few hundred bytes) or its equivalent
+// in elements. Without it many keys with few elements each can take much more memory than the cap suggests.
+// Keys over the cap are evicted on the next Process.
+func (registry *Registry[K, T]) SetSketchCost(cost int) {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+
+	registry.sketchCost = max(cost, 0)
+}
+
+// OnEvict registers callback called with key and its sketch every time a sketch is evicted because of the registry cap.
+// It can be used to persist or export the final estimate of the key. Registry is locked while callback runs,
+// so callback must not call methods of the registry.
+func (registry *Registry[K, T]) OnEvict(hook func(key K, sketch *CVM[T])) {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+
+	registry.onEvict = append(registry.onEvict, hook)
+}
@@ -0,0 +1,200 @@
+package cvm
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestRegistry(maxSampled int, policy EvictionPolicy) *Registry[string, int] {
+	return NewRegistry(maxSampled, policy, func(key string) *CVM[int] {
+		return NewCVM(1_000, intTestComparator)
+	})
+}
+
+func TestRegistry(t *testing.T) {
+	t.Run("Estimates", func(t *testing.T) {
+		registry := newTestRegistry(10_000, EvictLRU)
+		for i := 0; i < 10_000; i++ {
+			registry.Process(fmt.Sprint("page", i%10), i/10%((i%10+1)*10))
+		}
+		assert.Equal(t, 10, registry.Len())
+		for i := 0; i < 10; i++ {
+			n, ok := registry.Estimate(fmt.Sprint("page", i))
+			assert.True(t, ok)
+			assert.Equal(t, float64((i+1)*10), n)
+		}
+		_, ok := registry.Estimate("missing")
+		assert.False(t, ok)
+	})
+
+	t.Run("NewSketchPerKey", func(t *testing.T) {
+		registry := NewRegistry(10_000, EvictLRU, func(key string) *CVM[int] {
+			if key == "hot" {
+				return NewCVM(1_000, intTestComparator)
+			}
+			return NewCVM(10, intTestComparator)
+		})
+		for _, element := range newTestIntStream(1_000, 100) {
+			registry.Process("hot", element)
+			registry.Process("cold", element)
+		}
+		hot, _ := registry.Estimate("hot")
+		assert.Equal(t, 100.0, hot)
+		assert.Greater(t, registry.Sampled(), 100)
+		assert.LessOrEqual(t, registry.Sampled(), 110)
+	})
+
+	t.Run("EvictLRU", func(t *testing.T) {
+		registry := newTestRegistry(100, EvictLRU)
+		evicted := make([]string, 0)
+		registry.OnEvict(func(key string, sketch *CVM[int]) {
+			evicted = append(evicted, key)
+			assert.Equal(t, 20, sketch.N())
+		})
+		for i := 0; i < 10; i++ {
+			for _, element := range newTestIntStream(20, 20) {
+				registry.Process(fmt.Sprint("page", i), element)
+			}
+		}
+		assert.Equal(t, []string{"page0", "page1", "page2", "page3", "page4"}, evicted)
+		assert.Equal(t, 5, registry.Len())
+		assert.Equal(t, 100, registry.Sampled())
+	})
+
+	t.Run("SketchCost", func(t *testing.T) {
+		registry := newTestRegistry(50, EvictLRU)
+		registry.SetSketchCost(10)
+		for i := 0; i < 10; i++ {
+			registry.Process(fmt.Sprint("page", i), i)
+		}
+		assert.Equal(t, 4, registry.Len())
+		assert.Equal(t, 4, registry.Sampled())
+		_, ok := registry.Estimate("page9")
+		assert.True(t, ok)
+	})
+
+	t.Run("EvictLRURecentlyUsed", func(t *testing.T) {
+		registry := newTestRegistry(30, EvictLRU)
+		for _, element := range newTestIntStream(10, 10) {
+			registry.Process("a", element)
+			registry.Process("b", element)
+			registry.Process("c", element)
+		}
+		registry.Process("a", 0)
+		registry.Process("d", 0)
+		_, ok := registry.Estimate("b")
+		assert.False(t, ok)
+		_, ok = registry.Estimate("a")
+		assert.True(t, ok)
+	})
+
+	t.Run("EvictLeastValuable", func(t *testing.T) {
+		registry := newTestRegistry(60, EvictLeastValuable)
+		for _, element := range newTestIntStream(50, 50) {
+			registry.Process("big", element)
+		}
+		for i := 0; i < 20; i++ {
+			registry.Process(fmt.Sprint("small", i), 0)
+			assert.LessOrEqual(t, registry.Sampled(), 60)
+		}
+		n, ok := registry.Estimate("big")
+		assert.True(t, ok)
+		assert.Equal(t, 50.0, n)
+		_, ok = registry.Estimate("small19")
+		assert.True(t, ok)
+	})
+
+	t.Run("EvictLeastValuableCandidate", func(t *testing.T) {
+		for i := 0; i < 20; i++ {
+			registry := newTestRegistry(13, EvictLeastValuable)
+			evicted := make([]string, 0)
+			registry.OnEvict(func(key string, sketch *CVM[int]) { evicted = append(evicted, key) })
+			for _, page := range []struct {
+				key      string
+				distinct int
+			}{{"b", 5}, {"c", 3}, {"a", 1}, {"d", 4}} {
+				for _, element := range newTestIntStream(page.distinct, page.distinct) {
+					registry.Process(page.key, element)
+				}
+			}
+			registry.Process("e", 0)
+			assert.Equal(t, []string{"a"}, evicted)
+			assert.Equal(t, 4, registry.Len())
+			assert.Len(t, registry.keys, 4)
+			for index, key := range registry.keys {
+				assert.Equal(t, index, registry.entries[key].Value.(*registryEntry[string, int]).index)
+			}
+		}
+	})
+
+	t.Run("SingleKeyOverCap", func(t *testing.T) {
+		registry := newTestRegistry(10, EvictLRU)
+		for _, element := range newTestIntStream(100, 100) {
+			registry.Process("page", element)
+		}
+		assert.Equal(t, 1, registry.Len())
+		assert.Equal(t, 100, registry.Sampled())
+	})
+
+	t.Run("Iterate", func(t *testing.T) {
+		registry := newTestRegistry(1_000, EvictLRU)
+		registry.Process("a", 1)
+		registry.Process("b", 1)
+		registry.Process("b", 2)
+		registry.Process("c", 1)
+		registry.Process("a", 2)
+
+		keys := make([]string, 0)
+		estimates := make([]float64, 0)
+		registry.Estimates(func(key string, n float64) bool {
+			keys = append(keys, key)
+			estimates = append(estimates, n)
+			return true
+		})
+		assert.Equal(t, []string{"a", "c", "b"}, keys)
+		assert.Equal(t, []float64{2, 1, 2}, estimates)
+
+		keys = keys[:0]
+		registry.Estimates(func(key string, n float64) bool {
+			keys = append(keys, key)
+			return false
+		})
+		assert.Equal(t, []string{"a"}, keys)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		registry := newTestRegistry(1_000, EvictLRU)
+		registry.OnEvict(func(key string, sketch *CVM[int]) { t.Fatal("evicted") })
+		registry.Process("a", 1)
+		registry.Process("a", 2)
+		assert.True(t, registry.Delete("a"))
+		assert.False(t, registry.Delete("a"))
+		assert.Equal(t, 0, registry.Len())
+		assert.Equal(t, 0, registry.Sampled())
+	})
+
+	t.Run("Concurrent", func(t *testing.T) {
+		registry := newTestRegistry(500, EvictLRU)
+		var wg sync.WaitGroup
+		for worker := 0; worker < 8; worker++ {
+			wg.Add(1)
+			go func(worker int) {
+				defer wg.Done()
+				for _, element := range newTestIntStream(1_000, 100) {
+					registry.Process(fmt.Sprint("page", (element+worker)%20), element)
+				}
+			}(worker)
+		}
+		wg.Wait()
+		assert.LessOrEqual(t, registry.Sampled(), 500)
+		sampled := 0
+		registry.Estimates(func(key string, n float64) bool {
+			sampled += int(n)
+			return true
+		})
+		assert.Equal(t, registry.Sampled(), sampled)
+	})
+}
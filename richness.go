@@ -0,0 +1,88 @@
+package cvm
+
+import (
+	"errors"
+	"math"
+)
+
+// ErrUndefinedEstimate is returned when an estimator is undefined for the observed frequencies,
+// like Good–Turing estimate of a stream where every element was seen only once.
+var ErrUndefinedEstimate = errors.New("cvm: estimate is undefined for observed frequencies")
+
+// frequencies returns estimated number of distinct elements seen in stream (with its uncertainty), seen exactly once (f1)
+// and seen exactly twice (f2). Returns ErrOccurrencesNotTracked if occurrences are not tracked with maxK of at least minK.
+// Exact f2 needs minK of 3, with maxK of 2 elements seen twice can't be told from those seen more times and f2 counts both.
+func (cvm *CVM[T]) frequencies(minK int) (distinct Estimate, f1, f2 float64, err error) {
+	if cvm.maxK < minK {
+		return Estimate{}, 0, 0, ErrOccurrencesNotTracked
+	}
+	distinct, _ = cvm.NAtLeast(1)
+	twice, _ := cvm.NAtLeast(2)
+	f1 = distinct.Value - twice.Value
+	f2 = twice.Value
+	if cvm.maxK > 2 {
+		thrice, _ := cvm.NAtLeast(3)
+		f2 -= thrice.Value
+	}
+	return distinct, max(f1, 0), max(f2, 0), nil
+}
+
+// Chao1 estimates total number of distinct elements in the population the stream is drawn from, including elements
+// which were not seen yet, with Chao1 estimator: D + F1²/(2·F2), where D is number of distinct elements seen and F1 and F2 are
+// numbers of distinct elements seen exactly once and twice. Many elements seen once compared to those seen twice mean
+// many elements are still unseen. Without elements seen twice bias-corrected form D + F1·(F1-1)/2 is used.
+// Subtract N to get estimated number of unseen elements.
+//
+// F1 and F2 are estimated from the sample as by FrequencyHistogram. StdErr combines variance of Chao1 estimator
+// with uncertainty of D, but not uncertainty of F1 and F2, so it is too small when few sampled elements were seen twice.
+// Requires TrackOccurrences with maxK of at least 3 to tell elements seen exactly twice from those seen more times,
+// otherwise returns ErrOccurrencesNotTracked.
+func (cvm *CVM[T]) Chao1() (Estimate, error) {
+	distinct, f1, f2, err := cvm.frequencies(3)
+	if err != nil {
+		return Estimate{}, err
+	}
+
+	var value, variance float64
+	if f2 > 0 {
+		r := f1 / f2
+		value = distinct.Value + f1*f1/(2*f2)
+		variance = f2 * (r*r/2 + r*r*r + r*r*r*r/4)
+	} else {
+		value = distinct.Value + max(f1*(f1-1)/2, 0)
+		if value > 0 {
+			variance = max(f1*(f1-1)/2+f1*(2*f1-1)*(2*f1-1)/4-f1*f1*f1*f1/(4*value), 0)
+		}
+	}
+	return Estimate{
+		Value:   value,
+		StdErr:  math.Sqrt(variance + distinct.StdErr*distinct.StdErr),
+		Sampled: distinct.Sampled,
+	}, nil
+}
+
+// GoodTuring estimates total number of distinct elements in the population the stream is drawn from, including elements
+// which were not seen yet, from Good–Turing sample coverage. Coverage C = 1 - F1/n, where F1 is number of distinct elements
+// seen exactly once and n is number of processed elements, estimates share of the stream made of elements already seen,
+// and the estimate is D/C, where D is number of distinct elements seen. Unlike Chao1 it doesn't need elements seen twice,
+// but it assumes that all elements are about equally frequent, and otherwise underestimates.
+//
+// F1 is estimated from the sample as by FrequencyHistogram and n counts all processed elements, including those processed
+// before TrackOccurrences was called, so call it before processing. StdErr reflects uncertainty of D only.
+// Requires TrackOccurrences with maxK of at least 2, otherwise returns ErrOccurrencesNotTracked.
+// Returns ErrUndefinedEstimate if coverage is 0, which means no element was seen twice.
+func (cvm *CVM[T]) GoodTuring() (Estimate, error) {
+	distinct, f1, _, err := cvm.frequencies(2)
+	if err != nil {
+		return Estimate{}, err
+	}
+	coverage := 1 - f1/float64(cvm.total)
+	if cvm.total == 0 || coverage <= 0 {
+		return Estimate{}, ErrUndefinedEstimate
+	}
+	return Estimate{
+		Value:   distinct.Value / coverage,
+		StdErr:  distinct.StdErr / coverage,
+		Sampled: distinct.Sampled,
+	}, nil
+}
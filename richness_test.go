@@ -0,0 +1,116 @@
+package cvm
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestPopulationStream returns stream of total elements drawn uniformly from population of distinct elements.
+func newTestPopulationStream(total, population int, seed int64) []int {
+	random := rand.New(rand.NewSource(seed))
+	stream := make([]int, total)
+	for i := range stream {
+		stream[i] = random.Intn(population)
+	}
+	return stream
+}
+
+func TestChao1(t *testing.T) {
+	t.Run("NotTracked", func(t *testing.T) {
+		runner := NewCVM(10, intTestComparator)
+		_, err := runner.Chao1()
+		assert.ErrorIs(t, err, ErrOccurrencesNotTracked)
+		runner.TrackOccurrences(1)
+		_, err = runner.Chao1()
+		assert.ErrorIs(t, err, ErrOccurrencesNotTracked)
+		runner.TrackOccurrences(2)
+		_, err = runner.Chao1()
+		assert.ErrorIs(t, err, ErrOccurrencesNotTracked)
+	})
+
+	t.Run("ExactBuffer", func(t *testing.T) {
+		runner := NewCVM(1_000, intTestComparator)
+		runner.TrackOccurrences(4)
+		for _, element := range newTestFrequencyStream(1_000, 4) {
+			runner.Process(element)
+		}
+		estimate, err := runner.Chao1()
+		assert.Nil(t, err)
+		assert.Equal(t, 1_125.0, estimate.Value)
+		assert.InDelta(t, 20.9, estimate.StdErr, 0.1)
+		assert.Equal(t, 1_000, estimate.Sampled)
+	})
+
+	t.Run("NoDoubletons", func(t *testing.T) {
+		runner := NewCVM(1_000, intTestComparator)
+		runner.TrackOccurrences(3)
+		for _, element := range newTestIntStream(10, 10) {
+			runner.Process(element)
+		}
+		estimate, err := runner.Chao1()
+		assert.Nil(t, err)
+		assert.Equal(t, 55.0, estimate.Value)
+		assert.Greater(t, estimate.StdErr, 0.0)
+	})
+
+	t.Run("Population", func(t *testing.T) {
+		for _, bufferSize := range []int{10_000, 3_000} {
+			runner := NewCVM(bufferSize, intTestComparator)
+			runner.TrackOccurrences(3)
+			for _, element := range newTestPopulationStream(15_000, 10_000, 1) {
+				runner.Process(element)
+			}
+			assert.Less(t, runner.N(), 8_500)
+			estimate, err := runner.Chao1()
+			assert.Nil(t, err)
+			assert.InDelta(t, 10_000, estimate.Value, 1_500, "buffer size = %d", bufferSize)
+		}
+	})
+}
+
+func TestGoodTuring(t *testing.T) {
+	t.Run("NotTracked", func(t *testing.T) {
+		runner := NewCVM(10, intTestComparator)
+		_, err := runner.GoodTuring()
+		assert.ErrorIs(t, err, ErrOccurrencesNotTracked)
+	})
+
+	t.Run("ExactBuffer", func(t *testing.T) {
+		runner := NewCVM(1_000, intTestComparator)
+		runner.TrackOccurrences(4)
+		for _, element := range newTestFrequencyStream(1_000, 4) {
+			runner.Process(element)
+		}
+		estimate, err := runner.GoodTuring()
+		assert.Nil(t, err)
+		assert.InDelta(t, 1_111.1, estimate.Value, 0.1)
+		assert.Equal(t, 0.0, estimate.StdErr)
+	})
+
+	t.Run("Undefined", func(t *testing.T) {
+		runner := NewCVM(1_000, intTestComparator)
+		runner.TrackOccurrences(2)
+		_, err := runner.GoodTuring()
+		assert.ErrorIs(t, err, ErrUndefinedEstimate)
+		for _, element := range newTestIntStream(10, 10) {
+			runner.Process(element)
+		}
+		_, err = runner.GoodTuring()
+		assert.ErrorIs(t, err, ErrUndefinedEstimate)
+	})
+
+	t.Run("Population", func(t *testing.T) {
+		for _, bufferSize := range []int{10_000, 3_000} {
+			runner := NewCVM(bufferSize, intTestComparator)
+			runner.TrackOccurrences(2)
+			for _, element := range newTestPopulationStream(15_000, 10_000, 1) {
+				runner.Process(element)
+			}
+			estimate, err := runner.GoodTuring()
+			assert.Nil(t, err)
+			assert.InDelta(t, 10_000, estimate.Value, 1_500, "buffer size = %d", bufferSize)
+		}
+	})
+}
@@ -0,0 +1,347 @@
+// Package store provides an embedded, file-based store of CVM sketches by key, for keeping per-entity distinct counts
+// across restarts without an external database.
+//
+// Every key is kept in its own file, named by SHA-256 hash of the key and sharded into two levels of subdirectories
+// by the first and the second byte of the hash, so directories stay small even with many millions of keys.
+// A file holds a frame with CRC-32 checksum of the sketch encoded with MarshalBinary, so torn or damaged files are detected on read.
+// Writes are crash-safe: a new version of the sketch is written to a temporary file, synced to disk and renamed over the old file,
+// so after a crash every key holds either its old or its new sketch. Temporary files left by a crash are removed by Open.
+// UpdateBatch writes many keys with far fewer syncs than an Update per key. Recently used sketches are cached in memory.
+package store
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/tentameneu/cvm-go"
+	"github.com/tentameneu/cvm-go/internal/atomicfile"
+)
+
+// stripes is number of locks shared by keys. Keys with different locks are updated in parallel.
+const stripes = 64
+
+var (
+	// ErrNotFound is returned when there is no sketch for a key in the store.
+	ErrNotFound = errors.New("store: key not found")
+	// ErrCorrupt is returned when a sketch file fails checksum verification.
+	ErrCorrupt = errors.New("store: corrupt sketch file")
+	// ErrClosed is returned by methods of a closed Store.
+	ErrClosed = errors.New("store: store is closed")
+)
+
+// A Store keeps CVM sketches by key in files under a directory. Store is safe for concurrent use,
+// but a directory must not be opened by more than one Store at a time.
+type Store[T any] struct {
+	dir       string
+	newSketch func() *cvm.CVM[T]
+	locks     [stripes]sync.Mutex
+
+	mutex     sync.Mutex
+	cache     map[string]*list.Element
+	recency   *list.List
+	cacheSize int
+	closed    bool
+}
+
+type cacheEntry[T any] struct {
+	key    string
+	sketch *cvm.CVM[T]
+}
+
+// Open returns Store keeping sketches under dir, which is created if it doesn't exist.
+// Sketch for a new key is created with newSketch, which is also used to decode stored sketches,
+// so it has to use the same comparator every time the store is opened. At most cacheSize sketches are cached in memory.
+// Temporary files left under dir by writes interrupted by a crash are removed.
+func Open[T any](dir string, newSketch func() *cvm.CVM[T], cacheSize int) (*Store[T], error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	if err := atomicfile.RemoveTemp(dir); err != nil {
+		return nil, err
+	}
+	return &Store[T]{
+		dir:       dir,
+		newSketch: newSketch,
+		cache:     make(map[string]*list.Element),
+		recency:   list.New(),
+		cacheSize: cacheSize,
+	}, nil
+}
+
+// Update calls fn with sketch of key, creating a new sketch if key is not in the store, and writes the sketch back to disk.
+// Update is atomic: if fn returns an error, or the sketch can't be written, the stored sketch stays as it was and the error is returned.
+// Updates of the same key are serialized, fn must not call methods of the store.
+func (store *Store[T]) Update(key string, fn func(sketch *cvm.CVM[T]) error) error {
+	hash := sha256.Sum256([]byte(key))
+	lock := &store.locks[hash[0]%stripes]
+	lock.Lock()
+	defer lock.Unlock()
+
+	sketch, err := store.load(key, hash)
+	if errors.Is(err, ErrNotFound) {
+		sketch, err = store.newSketch(), nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := fn(sketch); err != nil {
+		store.uncache(key)
+		return err
+	}
+	if err := store.write(hash, sketch); err != nil {
+		store.uncache(key)
+		return err
+	}
+	store.cachePut(key, sketch)
+	return nil
+}
+
+// UpdateBatch calls fn with sketch of every key in keys, like Update, and writes all sketches back to disk together.
+// Sketches are synced to disk before any of them replaces its old file and every directory is synced once,
+// so it is much faster than an Update per key. If fn returns an error, no sketch is written and the error is returned.
+// After a crash or a failed write every key holds either its old or its new sketch, but some keys may be updated and others not.
+// Keys of the batch are locked together, fn must not call methods of the store.
+func (store *Store[T]) UpdateBatch(keys []string, fn func(key string, sketch *cvm.CVM[T]) error) error {
+	hashes := make(map[string][sha256.Size]byte, len(keys))
+	locked := make([]bool, stripes)
+	for _, key := range keys {
+		hash := sha256.Sum256([]byte(key))
+		hashes[key] = hash
+		locked[hash[0]%stripes] = true
+	}
+	for stripe := range locked {
+		if locked[stripe] {
+			store.locks[stripe].Lock()
+			defer store.locks[stripe].Unlock()
+		}
+	}
+
+	sketches := make(map[string]*cvm.CVM[T], len(hashes))
+	uncacheAll := func() {
+		for key := range sketches {
+			store.uncache(key)
+		}
+	}
+	for _, key := range keys {
+		sketch, ok := sketches[key]
+		if !ok {
+			var err error
+			sketch, err = store.load(key, hashes[key])
+			if errors.Is(err, ErrNotFound) {
+				sketch, err = store.newSketch(), nil
+			}
+			if err != nil {
+				uncacheAll()
+				return err
+			}
+			sketches[key] = sketch
+		}
+		if err := fn(key, sketch); err != nil {
+			uncacheAll()
+			return err
+		}
+	}
+
+	files := make(map[string][]byte, len(sketches))
+	for key, sketch := range sketches {
+		payload, err := sketch.MarshalBinary()
+		if err != nil {
+			uncacheAll()
+			return err
+		}
+		path := store.path(hashes[key])
+		if err := store.mkdirShard(path); err != nil {
+			uncacheAll()
+			return err
+		}
+		files[path] = payload
+	}
+	if err := atomicfile.WriteAll(files); err != nil {
+		uncacheAll()
+		return err
+	}
+	for key, sketch := range sketches {
+		store.cachePut(key, sketch)
+	}
+	return nil
+}
+
+// View calls fn with sketch of key. Returns ErrNotFound if key is not in the store.
+// fn must not modify the sketch or call methods of the store.
+func (store *Store[T]) View(key string, fn func(sketch *cvm.CVM[T]) error) error {
+	hash := sha256.Sum256([]byte(key))
+	lock := &store.locks[hash[0]%stripes]
+	lock.Lock()
+	defer lock.Unlock()
+
+	sketch, err := store.load(key, hash)
+	if err != nil {
+		return err
+	}
+	store.cachePut(key, sketch)
+	return fn(sketch)
+}
+
+// Estimate returns current estimated number of distinct elements for key. Returns ErrNotFound if key is not in the store.
+func (store *Store[T]) Estimate(key string) (float64, error) {
+	var n float64
+	err := store.View(key, func(sketch *cvm.CVM[T]) error {
+		n = sketch.Estimate()
+		return nil
+	})
+	return n, err
+}
+
+// Delete removes sketch of key from the store. Removal is synced to disk, so it survives a crash.
+// Returns ErrNotFound if key is not in the store.
+func (store *Store[T]) Delete(key string) error {
+	hash := sha256.Sum256([]byte(key))
+	lock := &store.locks[hash[0]%stripes]
+	lock.Lock()
+	defer lock.Unlock()
+
+	if err := store.checkClosed(); err != nil {
+		return err
+	}
+	store.uncache(key)
+	path := store.path(hash)
+	err := os.Remove(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+	return atomicfile.SyncDir(filepath.Dir(path))
+}
+
+// Close drops cached sketches. Every successful Update is already on disk, so nothing is lost by Close.
+// Methods of a closed store return ErrClosed.
+func (store *Store[T]) Close() error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	if store.closed {
+		return ErrClosed
+	}
+	store.closed = true
+	store.cache = nil
+	store.recency.Init()
+	return nil
+}
+
+func (store *Store[T]) checkClosed() error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	if store.closed {
+		return ErrClosed
+	}
+	return nil
+}
+
+// path returns path of file for key with hash, sharded into two levels of subdirectories by the first two bytes of hash.
+func (store *Store[T]) path(hash [sha256.Size]byte) string {
+	name := hex.EncodeToString(hash[:])
+	return filepath.Join(store.dir, name[:2], name[2:4], name)
+}
+
+// load returns sketch of key from cache or from disk. Lock of key has to be held.
+func (store *Store[T]) load(key string, hash [sha256.Size]byte) (*cvm.CVM[T], error) {
+	store.mutex.Lock()
+	if store.closed {
+		store.mutex.Unlock()
+		return nil, ErrClosed
+	}
+	if element, ok := store.cache[key]; ok {
+		store.recency.MoveToFront(element)
+		store.mutex.Unlock()
+		return element.Value.(*cacheEntry[T]).sketch, nil
+	}
+	store.mutex.Unlock()
+
+	path := store.path(hash)
+	payload, err := atomicfile.Read(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	if errors.Is(err, atomicfile.ErrCorrupt) {
+		return nil, fmt.Errorf("%w: %s", ErrCorrupt, path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	sketch := store.newSketch()
+	if err := sketch.UnmarshalBinary(payload); err != nil {
+		return nil, err
+	}
+	return sketch, nil
+}
+
+// write stores sketch in file for key with hash, replacing it atomically. Lock of key has to be held.
+func (store *Store[T]) write(hash [sha256.Size]byte, sketch *cvm.CVM[T]) error {
+	payload, err := sketch.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	path := store.path(hash)
+	if err := store.mkdirShard(path); err != nil {
+		return err
+	}
+	return atomicfile.Write(path, payload)
+}
+
+// mkdirShard creates both levels of shard directories of path which don't exist yet. Parent of every created directory
+// is synced, so the new directory and the file written into it survive a crash.
+func (store *Store[T]) mkdirShard(path string) error {
+	shard := filepath.Dir(path)
+	for _, dir := range []string{filepath.Dir(shard), shard} {
+		err := os.Mkdir(dir, 0o755)
+		if errors.Is(err, fs.ErrExist) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if err := atomicfile.SyncDir(filepath.Dir(dir)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (store *Store[T]) cachePut(key string, sketch *cvm.CVM[T]) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	if store.closed || store.cacheSize <= 0 {
+		return
+	}
+	if element, ok := store.cache[key]; ok {
+		element.Value.(*cacheEntry[T]).sketch = sketch
+		store.recency.MoveToFront(element)
+		return
+	}
+	store.cache[key] = store.recency.PushFront(&cacheEntry[T]{key: key, sketch: sketch})
+	for store.recency.Len() > store.cacheSize {
+		entry := store.recency.Remove(store.recency.Back()).(*cacheEntry[T])
+		delete(store.cache, entry.key)
+	}
+}
+
+func (store *Store[T]) uncache(key string) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	if element, ok := store.cache[key]; ok {
+		store.recency.Remove(element)
+		delete(store.cache, key)
+	}
+}
@@ -0,0 +1,223 @@
+package store
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tentameneu/cvm-go"
+)
+
+func newTestSketch() *cvm.CVM[int] {
+	return cvm.NewOrderedCVM[int](1_000)
+}
+
+func openTestStore(t *testing.T, dir string, cacheSize int) *Store[int] {
+	store, err := Open(dir, newTestSketch, cacheSize)
+	assert.Nil(t, err)
+	return store
+}
+
+func processAll(values ...int) func(sketch *cvm.CVM[int]) error {
+	return func(sketch *cvm.CVM[int]) error {
+		for _, value := range values {
+			sketch.Process(value)
+		}
+		return nil
+	}
+}
+
+func TestStore(t *testing.T) {
+	t.Run("UpdateAndReopen", func(t *testing.T) {
+		dir := t.TempDir()
+		store := openTestStore(t, dir, 10)
+		assert.Nil(t, store.Update("user:1", processAll(1, 2, 3)))
+		assert.Nil(t, store.Update("user:1", processAll(3, 4)))
+		assert.Nil(t, store.Update("user:2", processAll(1)))
+		n, err := store.Estimate("user:1")
+		assert.Nil(t, err)
+		assert.Equal(t, 4.0, n)
+		assert.Nil(t, store.Close())
+
+		store = openTestStore(t, dir, 10)
+		n, err = store.Estimate("user:1")
+		assert.Nil(t, err)
+		assert.Equal(t, 4.0, n)
+		n, err = store.Estimate("user:2")
+		assert.Nil(t, err)
+		assert.Equal(t, 1.0, n)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		store := openTestStore(t, t.TempDir(), 10)
+		_, err := store.Estimate("missing")
+		assert.ErrorIs(t, err, ErrNotFound)
+		assert.ErrorIs(t, store.Delete("missing"), ErrNotFound)
+	})
+
+	t.Run("Sharded", func(t *testing.T) {
+		dir := t.TempDir()
+		store := openTestStore(t, dir, 10)
+		assert.Nil(t, store.Update("user:1", processAll(1)))
+		hash := sha256.Sum256([]byte("user:1"))
+		_, err := os.Stat(store.path(hash))
+		assert.Nil(t, err)
+		assert.Equal(t, filepath.Join(dir, fmt.Sprintf("%02x", hash[0]), fmt.Sprintf("%02x", hash[1])), filepath.Dir(store.path(hash)))
+		entries, err := os.ReadDir(filepath.Dir(store.path(hash)))
+		assert.Nil(t, err)
+		assert.Len(t, entries, 1)
+	})
+
+	t.Run("Atomic", func(t *testing.T) {
+		store := openTestStore(t, t.TempDir(), 10)
+		assert.Nil(t, store.Update("user:1", processAll(1, 2)))
+		failure := errors.New("failure")
+		err := store.Update("user:1", func(sketch *cvm.CVM[int]) error {
+			sketch.Process(3)
+			return failure
+		})
+		assert.ErrorIs(t, err, failure)
+		n, err := store.Estimate("user:1")
+		assert.Nil(t, err)
+		assert.Equal(t, 2.0, n)
+	})
+
+	t.Run("UpdateBatch", func(t *testing.T) {
+		dir := t.TempDir()
+		store := openTestStore(t, dir, 2)
+		assert.Nil(t, store.Update("user:0", processAll(1)))
+		keys := make([]string, 0)
+		for i := 0; i < 10; i++ {
+			keys = append(keys, fmt.Sprint("user:", i))
+		}
+		keys = append(keys, "user:0")
+		err := store.UpdateBatch(keys, func(key string, sketch *cvm.CVM[int]) error {
+			sketch.Process(len(key) + sketch.N())
+			return nil
+		})
+		assert.Nil(t, err)
+		assert.Nil(t, store.Close())
+
+		store = openTestStore(t, dir, 2)
+		n, err := store.Estimate("user:0")
+		assert.Nil(t, err)
+		assert.Equal(t, 3.0, n)
+		for i := 1; i < 10; i++ {
+			n, err := store.Estimate(fmt.Sprint("user:", i))
+			assert.Nil(t, err)
+			assert.Equal(t, 1.0, n)
+		}
+	})
+
+	t.Run("UpdateBatchAtomic", func(t *testing.T) {
+		store := openTestStore(t, t.TempDir(), 10)
+		assert.Nil(t, store.Update("user:1", processAll(1, 2)))
+		failure := errors.New("failure")
+		err := store.UpdateBatch([]string{"user:1", "user:2", "user:3"}, func(key string, sketch *cvm.CVM[int]) error {
+			sketch.Process(3)
+			if key == "user:3" {
+				return failure
+			}
+			return nil
+		})
+		assert.ErrorIs(t, err, failure)
+		n, err := store.Estimate("user:1")
+		assert.Nil(t, err)
+		assert.Equal(t, 2.0, n)
+		_, err = store.Estimate("user:2")
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("RemoveTemp", func(t *testing.T) {
+		dir := t.TempDir()
+		store := openTestStore(t, dir, 10)
+		assert.Nil(t, store.Update("user:1", processAll(1)))
+		assert.Nil(t, store.Close())
+		shard := filepath.Dir(store.path(sha256.Sum256([]byte("user:1"))))
+		stale := filepath.Join(shard, ".tmp-123")
+		assert.Nil(t, os.WriteFile(stale, []byte("torn"), 0o644))
+
+		store = openTestStore(t, dir, 10)
+		_, err := os.Stat(stale)
+		assert.ErrorIs(t, err, os.ErrNotExist)
+		entries, err := os.ReadDir(shard)
+		assert.Nil(t, err)
+		assert.Len(t, entries, 1)
+		n, err := store.Estimate("user:1")
+		assert.Nil(t, err)
+		assert.Equal(t, 1.0, n)
+	})
+
+	t.Run("CacheEviction", func(t *testing.T) {
+		store := openTestStore(t, t.TempDir(), 2)
+		for i := 0; i < 10; i++ {
+			assert.Nil(t, store.Update(fmt.Sprint("user:", i), processAll(i, i+1)))
+		}
+		assert.Equal(t, 2, store.recency.Len())
+		for i := 0; i < 10; i++ {
+			n, err := store.Estimate(fmt.Sprint("user:", i))
+			assert.Nil(t, err)
+			assert.Equal(t, 2.0, n)
+		}
+	})
+
+	t.Run("Corrupt", func(t *testing.T) {
+		dir := t.TempDir()
+		store := openTestStore(t, dir, 0)
+		assert.Nil(t, store.Update("user:1", processAll(1, 2)))
+		path := store.path(sha256.Sum256([]byte("user:1")))
+		data, err := os.ReadFile(path)
+		assert.Nil(t, err)
+		data[len(data)-1] ^= 0xff
+		assert.Nil(t, os.WriteFile(path, data, 0o644))
+		_, err = store.Estimate("user:1")
+		assert.ErrorIs(t, err, ErrCorrupt)
+
+		assert.Nil(t, os.WriteFile(path, data[:4], 0o644))
+		_, err = store.Estimate("user:1")
+		assert.ErrorIs(t, err, ErrCorrupt)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		store := openTestStore(t, t.TempDir(), 10)
+		assert.Nil(t, store.Update("user:1", processAll(1)))
+		assert.Nil(t, store.Delete("user:1"))
+		_, err := store.Estimate("user:1")
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("Closed", func(t *testing.T) {
+		store := openTestStore(t, t.TempDir(), 10)
+		assert.Nil(t, store.Close())
+		assert.ErrorIs(t, store.Close(), ErrClosed)
+		assert.ErrorIs(t, store.Update("user:1", processAll(1)), ErrClosed)
+		_, err := store.Estimate("user:1")
+		assert.ErrorIs(t, err, ErrClosed)
+		assert.ErrorIs(t, store.Delete("user:1"), ErrClosed)
+	})
+
+	t.Run("Concurrent", func(t *testing.T) {
+		store := openTestStore(t, t.TempDir(), 5)
+		var wg sync.WaitGroup
+		for worker := 0; worker < 8; worker++ {
+			wg.Add(1)
+			go func(worker int) {
+				defer wg.Done()
+				for i := 0; i < 100; i++ {
+					assert.Nil(t, store.Update(fmt.Sprint("user:", i%10), processAll(worker*100+i)))
+				}
+			}(worker)
+		}
+		wg.Wait()
+		for i := 0; i < 10; i++ {
+			n, err := store.Estimate(fmt.Sprint("user:", i))
+			assert.Nil(t, err)
+			assert.Equal(t, 80.0, n)
+		}
+	})
+}
@@ -3,6 +3,9 @@ package cvm
 import (
 	"fmt"
 	"io"
+	"strconv"
+
+	"github.com/tentameneu/cvm-go/internal/treap"
 )
 
 // Comparator is a function used to compare elements while saving them to a treap buffer.
@@ -12,162 +15,87 @@ import (
 // return > 0 (positive int) if x > y.
 type Comparator[T any] func(x, y T) int
 
+// treapBuffer is a treap keeping sampled elements, see treap.Tree. Nodes of CVM tracking occurrences
+// carry them as Data, see CVM.TrackOccurrences.
 type treapBuffer[T any] struct {
-	root        *node[T]
-	currentSize int
-	comparator  Comparator[T]
-}
-
-type node[T any] struct {
-	value    T
-	priority float64
-	left     *node[T]
-	right    *node[T]
+	*treap.Tree[T, *occurrences]
 }
 
-func newNode[T any](value T, priority float64) *node[T] {
-	return &node[T]{
-		value:    value,
-		priority: priority,
-		left:     nil,
-		right:    nil,
-	}
+func newNode[T any](value T, priority float64) *treap.Node[T, *occurrences] {
+	return treap.NewNode[T, *occurrences](value, priority)
 }
 
 func newTreapBuffer[T any](comp Comparator[T]) *treapBuffer[T] {
 	return &treapBuffer[T]{
-		root:        nil,
-		currentSize: 0,
-		comparator:  comp,
+		Tree: treap.New[T, *occurrences](comp),
 	}
 }
 
-func rightRotate[T any](node *node[T]) *node[T] {
-	pivot := node.left
-	temp := pivot.right
-	pivot.right = node
-	node.left = temp
-	return pivot
-}
-
-func leftRotate[T any](node *node[T]) *node[T] {
-	pivot := node.right
-	temp := pivot.left
-	pivot.left = node
-	node.right = temp
-	return pivot
+func (tb *treapBuffer[T]) printBasicInfo(writer io.Writer) {
+	fmt.Fprintf(writer, "Size: %d\n", tb.Size)
+	fmt.Fprint(writer, "Root: ")
+	if tb.Root != nil {
+		printNode(writer, tb.Root)
+	} else {
+		fmt.Fprintln(writer, tb.Root)
+	}
 }
 
-func (tb *treapBuffer[T]) insert(newNode *node[T]) {
-	tb.delete(newNode.value)
-	tb.root = insertNode(tb.root, newNode, tb.comparator)
-	tb.currentSize++
+func (tb *treapBuffer[T]) printInOrder(writer io.Writer) {
+	printFrom(writer, tb.Root)
 }
 
-func insertNode[T any](root, newNode *node[T], comp Comparator[T]) *node[T] {
-	if root == nil {
-		return newNode
+func printFrom[T any](writer io.Writer, node *treap.Node[T, *occurrences]) {
+	if node != nil {
+		printFrom(writer, node.Left)
+		printNode(writer, node)
+		printFrom(writer, node.Right)
 	}
-
-	if comp(root.value, newNode.value) > 0 {
-		root.left = insertNode(root.left, newNode, comp)
-		if root.priority < newNode.priority {
-			return rightRotate(root)
-		}
-		return root
-	} else if comp(root.value, newNode.value) < 0 {
-		root.right = insertNode(root.right, newNode, comp)
-		if root.priority < newNode.priority {
-			return leftRotate(root)
-		}
-		return root
-	}
-
-	return root
 }
 
-func (tb *treapBuffer[T]) delete(value T) {
-	root, deleted := deleteNode(tb.root, value, tb.comparator, false)
-	tb.root = root
-	if deleted {
-		tb.currentSize--
+func (tb *treapBuffer[T]) printTree(writer io.Writer) {
+	if tb.Root == nil {
+		fmt.Fprintln(writer, tb.Root)
+		return
 	}
+	printSubtree(writer, tb.Root, "", 0)
 }
 
-func deleteNode[T any](root *node[T], value T, comp Comparator[T], found bool) (*node[T], bool) {
-	if root == nil {
-		return root, found
+func printSubtree[T any](writer io.Writer, node *treap.Node[T, *occurrences], side string, depth int) {
+	if node == nil {
+		return
 	}
-
-	switch {
-	case comp(value, root.value) < 0:
-		root.left, found = deleteNode(root.left, value, comp, found)
-	case comp(value, root.value) > 0:
-		root.right, found = deleteNode(root.right, value, comp, found)
-	case comp(value, root.value) == 0:
-		switch {
-		case root.left == nil:
-			root = root.right
-		case root.right == nil:
-			root = root.left
-		default:
-			if root.left.priority < root.right.priority {
-				root = leftRotate(root)
-				root.left, _ = deleteNode(root.left, value, comp, found)
-			} else {
-				root = rightRotate(root)
-				root.right, _ = deleteNode(root.right, value, comp, found)
-			}
-		}
-		found = true
-	}
-
-	return root, found
+	fmt.Fprintf(writer, "%*s%s", 2*depth, "", side)
+	printNode(writer, node)
+	printSubtree(writer, node.Left, "L: ", depth+1)
+	printSubtree(writer, node.Right, "R: ", depth+1)
 }
 
-func (tb *treapBuffer[T]) contains(value T) bool {
-	current := tb.root
-
-	for current != nil {
-		if tb.comparator(value, current.value) == 0 {
-			return true
-		}
-
-		if tb.comparator(value, current.value) < 0 {
-			current = current.left
-		} else {
-			current = current.right
-		}
-	}
-
-	return false
+func (tb *treapBuffer[T]) printDOT(writer io.Writer) {
+	fmt.Fprintln(writer, "digraph treap {")
+	printDOTNode(writer, tb.Root, 0)
+	fmt.Fprintln(writer, "}")
 }
 
-func (tb *treapBuffer[T]) printBasicInfo(writer io.Writer) {
-	fmt.Fprintf(writer, "Size: %d\n", tb.currentSize)
-	fmt.Fprint(writer, "Root: ")
-	if tb.root != nil {
-		printNode(writer, tb.root)
-	} else {
-		fmt.Fprintln(writer, tb.root)
+// printDOTNode prints node and its subtree with ids assigned in pre-order starting from id.
+// Returns the next free id.
+func printDOTNode[T any](writer io.Writer, node *treap.Node[T, *occurrences], id int) int {
+	if node == nil {
+		return id
+	}
+	fmt.Fprintf(writer, "\tn%d [label=%s];\n", id, strconv.Quote(fmt.Sprintf("%v\n%f", node.Value, node.Priority)))
+	next := id + 1
+	if node.Left != nil {
+		fmt.Fprintf(writer, "\tn%d -> n%d [label=\"L\"];\n", id, next)
+		next = printDOTNode(writer, node.Left, next)
 	}
+	if node.Right != nil {
+		fmt.Fprintf(writer, "\tn%d -> n%d [label=\"R\"];\n", id, next)
+		next = printDOTNode(writer, node.Right, next)
+	}
+	return next
 }
 
-// func (tb *treapBuffer) printDetails() {
-// 	fmt.Printf("Size: %d\n", tb.currentSize)
-// 	fmt.Printf("Root: ")
-// 	printNode(tb.root)
-// 	printFrom((tb.root))
-// }
-
-// func printFrom(node *node) {
-// 	if node != nil {
-// 		printFrom(node.left)
-// 		printNode(node)
-// 		printFrom(node.right)
-// 	}
-// }
-
-func printNode[T any](writer io.Writer, node *node[T]) {
-	fmt.Fprintf(writer, "<Value: %v, Priority: %f>\n", node.value, node.priority)
+func printNode[T any](writer io.Writer, node *treap.Node[T, *occurrences]) {
+	fmt.Fprintf(writer, "<Value: %v, Priority: %f>\n", node.Value, node.Priority)
 }
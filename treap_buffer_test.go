@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/tentameneu/cvm-go/internal/treap"
 )
 
 var intTestComparator = func(x, y int) int { return x - y }
@@ -68,32 +69,33 @@ func TestNew(t *testing.T) {
 	t.Run("Int", func(t *testing.T) {
 		t.Run("TreapBuffer", func(t *testing.T) {
 			buffer := newTreapBuffer(intTestComparator)
-			assert.Nil(t, buffer.root)
-			assert.Equal(t, 0, buffer.currentSize)
+			assert.Nil(t, buffer.Root)
+			assert.Equal(t, 0, buffer.Size)
 		})
 
 		t.Run("Node", func(t *testing.T) {
 			node := newNode(123, 0.456)
-			assert.Equal(t, 123, node.value)
-			assert.Equal(t, 0.456, node.priority)
-			assert.Nil(t, node.left)
-			assert.Nil(t, node.right)
+			assert.Equal(t, 123, node.Value)
+			assert.Equal(t, 0.456, node.Priority)
+			assert.Equal(t, 1, node.SubtreeSize())
+			assert.Nil(t, node.Left)
+			assert.Nil(t, node.Right)
 		})
 	})
 
 	t.Run("Float", func(t *testing.T) {
 		t.Run("TreapBuffer", func(t *testing.T) {
 			buffer := newTreapBuffer(floatTestComparator)
-			assert.Nil(t, buffer.root)
-			assert.Equal(t, 0, buffer.currentSize)
+			assert.Nil(t, buffer.Root)
+			assert.Equal(t, 0, buffer.Size)
 		})
 
 		t.Run("Node", func(t *testing.T) {
 			node := newNode(123.456, 0.789)
-			assert.Equal(t, 123.456, node.value)
-			assert.Equal(t, 0.789, node.priority)
-			assert.Nil(t, node.left)
-			assert.Nil(t, node.right)
+			assert.Equal(t, 123.456, node.Value)
+			assert.Equal(t, 0.789, node.Priority)
+			assert.Nil(t, node.Left)
+			assert.Nil(t, node.Right)
 		})
 	})
 }
@@ -103,83 +105,83 @@ func TestInsert(t *testing.T) {
 		buffer := newTreapBuffer(intTestComparator)
 
 		t.Run("OnEmpty", func(t *testing.T) {
-			buffer.insert(newNode(30, 0.200))
-			assert.Equal(t, 1, buffer.currentSize)
-			assert.Equal(t, 30, buffer.root.value)
-			assert.Equal(t, 0.200, buffer.root.priority)
+			buffer.Insert(newNode(30, 0.200))
+			assert.Equal(t, 1, buffer.Size)
+			assert.Equal(t, 30, buffer.Root.Value)
+			assert.Equal(t, 0.200, buffer.Root.Priority)
 		})
 
 		t.Run("NewRightLeaf", func(t *testing.T) {
-			buffer.insert(newNode(40, 0.100))
-			assert.Equal(t, 2, buffer.currentSize)
-			assert.Equal(t, 30, buffer.root.value)
-			assert.Equal(t, 0.200, buffer.root.priority)
-			assert.Nil(t, buffer.root.left)
-			assert.Equal(t, 40, buffer.root.right.value)
-			assert.Equal(t, 0.100, buffer.root.right.priority)
+			buffer.Insert(newNode(40, 0.100))
+			assert.Equal(t, 2, buffer.Size)
+			assert.Equal(t, 30, buffer.Root.Value)
+			assert.Equal(t, 0.200, buffer.Root.Priority)
+			assert.Nil(t, buffer.Root.Left)
+			assert.Equal(t, 40, buffer.Root.Right.Value)
+			assert.Equal(t, 0.100, buffer.Root.Right.Priority)
 		})
 
 		t.Run("NewRoot", func(t *testing.T) {
-			buffer.insert(newNode(20, 0.300))
-			assert.Equal(t, 3, buffer.currentSize)
-			assert.Equal(t, 20, buffer.root.value)
-			assert.Equal(t, 0.300, buffer.root.priority)
-			assert.Nil(t, buffer.root.left)
-			assert.Equal(t, 30, buffer.root.right.value)
-			assert.Equal(t, 0.200, buffer.root.right.priority)
-			assert.Nil(t, buffer.root.right.left)
-			assert.Equal(t, 40, buffer.root.right.right.value)
-			assert.Equal(t, 0.100, buffer.root.right.right.priority)
+			buffer.Insert(newNode(20, 0.300))
+			assert.Equal(t, 3, buffer.Size)
+			assert.Equal(t, 20, buffer.Root.Value)
+			assert.Equal(t, 0.300, buffer.Root.Priority)
+			assert.Nil(t, buffer.Root.Left)
+			assert.Equal(t, 30, buffer.Root.Right.Value)
+			assert.Equal(t, 0.200, buffer.Root.Right.Priority)
+			assert.Nil(t, buffer.Root.Right.Left)
+			assert.Equal(t, 40, buffer.Root.Right.Right.Value)
+			assert.Equal(t, 0.100, buffer.Root.Right.Right.Priority)
 		})
 
 		t.Run("NewLeftLeaf", func(t *testing.T) {
-			buffer.insert(newNode(10, 0.210))
-			assert.Equal(t, 4, buffer.currentSize)
-			assert.Equal(t, 20, buffer.root.value)
-			assert.Equal(t, 0.300, buffer.root.priority)
-			assert.Equal(t, 10, buffer.root.left.value)
-			assert.Equal(t, 0.210, buffer.root.left.priority)
-			assert.Equal(t, 30, buffer.root.right.value)
-			assert.Equal(t, 0.200, buffer.root.right.priority)
-			assert.Nil(t, buffer.root.right.left)
-			assert.Equal(t, 40, buffer.root.right.right.value)
-			assert.Equal(t, 0.100, buffer.root.right.right.priority)
+			buffer.Insert(newNode(10, 0.210))
+			assert.Equal(t, 4, buffer.Size)
+			assert.Equal(t, 20, buffer.Root.Value)
+			assert.Equal(t, 0.300, buffer.Root.Priority)
+			assert.Equal(t, 10, buffer.Root.Left.Value)
+			assert.Equal(t, 0.210, buffer.Root.Left.Priority)
+			assert.Equal(t, 30, buffer.Root.Right.Value)
+			assert.Equal(t, 0.200, buffer.Root.Right.Priority)
+			assert.Nil(t, buffer.Root.Right.Left)
+			assert.Equal(t, 40, buffer.Root.Right.Right.Value)
+			assert.Equal(t, 0.100, buffer.Root.Right.Right.Priority)
 		})
 
 		t.Run("MiddleWithRotate", func(t *testing.T) {
-			buffer.insert(newNode(15, 0.220))
-			assert.Equal(t, 5, buffer.currentSize)
-			assert.Equal(t, 20, buffer.root.value)
-			assert.Equal(t, 0.300, buffer.root.priority)
-			assert.Equal(t, 15, buffer.root.left.value)
-			assert.Equal(t, 0.220, buffer.root.left.priority)
-			assert.Equal(t, 10, buffer.root.left.left.value)
-			assert.Equal(t, 0.210, buffer.root.left.left.priority)
-			assert.Nil(t, buffer.root.left.right)
-			assert.Equal(t, 30, buffer.root.right.value)
-			assert.Equal(t, 0.200, buffer.root.right.priority)
-			assert.Nil(t, buffer.root.right.left)
-			assert.Equal(t, 40, buffer.root.right.right.value)
-			assert.Equal(t, 0.100, buffer.root.right.right.priority)
+			buffer.Insert(newNode(15, 0.220))
+			assert.Equal(t, 5, buffer.Size)
+			assert.Equal(t, 20, buffer.Root.Value)
+			assert.Equal(t, 0.300, buffer.Root.Priority)
+			assert.Equal(t, 15, buffer.Root.Left.Value)
+			assert.Equal(t, 0.220, buffer.Root.Left.Priority)
+			assert.Equal(t, 10, buffer.Root.Left.Left.Value)
+			assert.Equal(t, 0.210, buffer.Root.Left.Left.Priority)
+			assert.Nil(t, buffer.Root.Left.Right)
+			assert.Equal(t, 30, buffer.Root.Right.Value)
+			assert.Equal(t, 0.200, buffer.Root.Right.Priority)
+			assert.Nil(t, buffer.Root.Right.Left)
+			assert.Equal(t, 40, buffer.Root.Right.Right.Value)
+			assert.Equal(t, 0.100, buffer.Root.Right.Right.Priority)
 		})
 
 		t.Run("NewRootLowerValue", func(t *testing.T) {
-			buffer.insert(newNode(18, 0.310))
-			assert.Equal(t, 6, buffer.currentSize)
-			assert.Equal(t, 18, buffer.root.value)
-			assert.Equal(t, 0.310, buffer.root.priority)
-			assert.Equal(t, 15, buffer.root.left.value)
-			assert.Equal(t, 0.220, buffer.root.left.priority)
-			assert.Equal(t, 20, buffer.root.right.value)
-			assert.Equal(t, 0.300, buffer.root.right.priority)
-			assert.Equal(t, 10, buffer.root.left.left.value)
-			assert.Equal(t, 0.210, buffer.root.left.left.priority)
-			assert.Nil(t, buffer.root.left.right)
-			assert.Equal(t, 30, buffer.root.right.right.value)
-			assert.Equal(t, 0.200, buffer.root.right.right.priority)
-			assert.Nil(t, buffer.root.right.left)
-			assert.Equal(t, 40, buffer.root.right.right.right.value)
-			assert.Equal(t, 0.100, buffer.root.right.right.right.priority)
+			buffer.Insert(newNode(18, 0.310))
+			assert.Equal(t, 6, buffer.Size)
+			assert.Equal(t, 18, buffer.Root.Value)
+			assert.Equal(t, 0.310, buffer.Root.Priority)
+			assert.Equal(t, 15, buffer.Root.Left.Value)
+			assert.Equal(t, 0.220, buffer.Root.Left.Priority)
+			assert.Equal(t, 20, buffer.Root.Right.Value)
+			assert.Equal(t, 0.300, buffer.Root.Right.Priority)
+			assert.Equal(t, 10, buffer.Root.Left.Left.Value)
+			assert.Equal(t, 0.210, buffer.Root.Left.Left.Priority)
+			assert.Nil(t, buffer.Root.Left.Right)
+			assert.Equal(t, 30, buffer.Root.Right.Right.Value)
+			assert.Equal(t, 0.200, buffer.Root.Right.Right.Priority)
+			assert.Nil(t, buffer.Root.Right.Left)
+			assert.Equal(t, 40, buffer.Root.Right.Right.Right.Value)
+			assert.Equal(t, 0.100, buffer.Root.Right.Right.Right.Priority)
 		})
 	})
 
@@ -187,83 +189,83 @@ func TestInsert(t *testing.T) {
 		buffer := newTreapBuffer(floatTestComparator)
 
 		t.Run("OnEmpty", func(t *testing.T) {
-			buffer.insert(newNode(30.30, 0.200))
-			assert.Equal(t, 1, buffer.currentSize)
-			assert.Equal(t, 30.30, buffer.root.value)
-			assert.Equal(t, 0.200, buffer.root.priority)
+			buffer.Insert(newNode(30.30, 0.200))
+			assert.Equal(t, 1, buffer.Size)
+			assert.Equal(t, 30.30, buffer.Root.Value)
+			assert.Equal(t, 0.200, buffer.Root.Priority)
 		})
 
 		t.Run("NewRightLeaf", func(t *testing.T) {
-			buffer.insert(newNode(40.40, 0.100))
-			assert.Equal(t, 2, buffer.currentSize)
-			assert.Equal(t, 30.30, buffer.root.value)
-			assert.Equal(t, 0.200, buffer.root.priority)
-			assert.Nil(t, buffer.root.left)
-			assert.Equal(t, 40.40, buffer.root.right.value)
-			assert.Equal(t, 0.100, buffer.root.right.priority)
+			buffer.Insert(newNode(40.40, 0.100))
+			assert.Equal(t, 2, buffer.Size)
+			assert.Equal(t, 30.30, buffer.Root.Value)
+			assert.Equal(t, 0.200, buffer.Root.Priority)
+			assert.Nil(t, buffer.Root.Left)
+			assert.Equal(t, 40.40, buffer.Root.Right.Value)
+			assert.Equal(t, 0.100, buffer.Root.Right.Priority)
 		})
 
 		t.Run("NewRoot", func(t *testing.T) {
-			buffer.insert(newNode(20.20, 0.300))
-			assert.Equal(t, 3, buffer.currentSize)
-			assert.Equal(t, 20.20, buffer.root.value)
-			assert.Equal(t, 0.300, buffer.root.priority)
-			assert.Nil(t, buffer.root.left)
-			assert.Equal(t, 30.30, buffer.root.right.value)
-			assert.Equal(t, 0.200, buffer.root.right.priority)
-			assert.Nil(t, buffer.root.right.left)
-			assert.Equal(t, 40.40, buffer.root.right.right.value)
-			assert.Equal(t, 0.100, buffer.root.right.right.priority)
+			buffer.Insert(newNode(20.20, 0.300))
+			assert.Equal(t, 3, buffer.Size)
+			assert.Equal(t, 20.20, buffer.Root.Value)
+			assert.Equal(t, 0.300, buffer.Root.Priority)
+			assert.Nil(t, buffer.Root.Left)
+			assert.Equal(t, 30.30, buffer.Root.Right.Value)
+			assert.Equal(t, 0.200, buffer.Root.Right.Priority)
+			assert.Nil(t, buffer.Root.Right.Left)
+			assert.Equal(t, 40.40, buffer.Root.Right.Right.Value)
+			assert.Equal(t, 0.100, buffer.Root.Right.Right.Priority)
 		})
 
 		t.Run("NewLeftLeaf", func(t *testing.T) {
-			buffer.insert(newNode(10.10, 0.210))
-			assert.Equal(t, 4, buffer.currentSize)
-			assert.Equal(t, 20.20, buffer.root.value)
-			assert.Equal(t, 0.300, buffer.root.priority)
-			assert.Equal(t, 10.10, buffer.root.left.value)
-			assert.Equal(t, 0.210, buffer.root.left.priority)
-			assert.Equal(t, 30.30, buffer.root.right.value)
-			assert.Equal(t, 0.200, buffer.root.right.priority)
-			assert.Nil(t, buffer.root.right.left)
-			assert.Equal(t, 40.40, buffer.root.right.right.value)
-			assert.Equal(t, 0.100, buffer.root.right.right.priority)
+			buffer.Insert(newNode(10.10, 0.210))
+			assert.Equal(t, 4, buffer.Size)
+			assert.Equal(t, 20.20, buffer.Root.Value)
+			assert.Equal(t, 0.300, buffer.Root.Priority)
+			assert.Equal(t, 10.10, buffer.Root.Left.Value)
+			assert.Equal(t, 0.210, buffer.Root.Left.Priority)
+			assert.Equal(t, 30.30, buffer.Root.Right.Value)
+			assert.Equal(t, 0.200, buffer.Root.Right.Priority)
+			assert.Nil(t, buffer.Root.Right.Left)
+			assert.Equal(t, 40.40, buffer.Root.Right.Right.Value)
+			assert.Equal(t, 0.100, buffer.Root.Right.Right.Priority)
 		})
 
 		t.Run("MiddleWithRotate", func(t *testing.T) {
-			buffer.insert(newNode(15.15, 0.220))
-			assert.Equal(t, 5, buffer.currentSize)
-			assert.Equal(t, 20.20, buffer.root.value)
-			assert.Equal(t, 0.300, buffer.root.priority)
-			assert.Equal(t, 15.15, buffer.root.left.value)
-			assert.Equal(t, 0.220, buffer.root.left.priority)
-			assert.Equal(t, 10.10, buffer.root.left.left.value)
-			assert.Equal(t, 0.210, buffer.root.left.left.priority)
-			assert.Nil(t, buffer.root.left.right)
-			assert.Equal(t, 30.30, buffer.root.right.value)
-			assert.Equal(t, 0.200, buffer.root.right.priority)
-			assert.Nil(t, buffer.root.right.left)
-			assert.Equal(t, 40.40, buffer.root.right.right.value)
-			assert.Equal(t, 0.100, buffer.root.right.right.priority)
+			buffer.Insert(newNode(15.15, 0.220))
+			assert.Equal(t, 5, buffer.Size)
+			assert.Equal(t, 20.20, buffer.Root.Value)
+			assert.Equal(t, 0.300, buffer.Root.Priority)
+			assert.Equal(t, 15.15, buffer.Root.Left.Value)
+			assert.Equal(t, 0.220, buffer.Root.Left.Priority)
+			assert.Equal(t, 10.10, buffer.Root.Left.Left.Value)
+			assert.Equal(t, 0.210, buffer.Root.Left.Left.Priority)
+			assert.Nil(t, buffer.Root.Left.Right)
+			assert.Equal(t, 30.30, buffer.Root.Right.Value)
+			assert.Equal(t, 0.200, buffer.Root.Right.Priority)
+			assert.Nil(t, buffer.Root.Right.Left)
+			assert.Equal(t, 40.40, buffer.Root.Right.Right.Value)
+			assert.Equal(t, 0.100, buffer.Root.Right.Right.Priority)
 		})
 
 		t.Run("NewRootLowerValue", func(t *testing.T) {
-			buffer.insert(newNode(18.18, 0.310))
-			assert.Equal(t, 6, buffer.currentSize)
-			assert.Equal(t, 18.18, buffer.root.value)
-			assert.Equal(t, 0.310, buffer.root.priority)
-			assert.Equal(t, 15.15, buffer.root.left.value)
-			assert.Equal(t, 0.220, buffer.root.left.priority)
-			assert.Equal(t, 20.20, buffer.root.right.value)
-			assert.Equal(t, 0.300, buffer.root.right.priority)
-			assert.Equal(t, 10.10, buffer.root.left.left.value)
-			assert.Equal(t, 0.210, buffer.root.left.left.priority)
-			assert.Nil(t, buffer.root.left.right)
-			assert.Equal(t, 30.30, buffer.root.right.right.value)
-			assert.Equal(t, 0.200, buffer.root.right.right.priority)
-			assert.Nil(t, buffer.root.right.left)
-			assert.Equal(t, 40.40, buffer.root.right.right.right.value)
-			assert.Equal(t, 0.100, buffer.root.right.right.right.priority)
+			buffer.Insert(newNode(18.18, 0.310))
+			assert.Equal(t, 6, buffer.Size)
+			assert.Equal(t, 18.18, buffer.Root.Value)
+			assert.Equal(t, 0.310, buffer.Root.Priority)
+			assert.Equal(t, 15.15, buffer.Root.Left.Value)
+			assert.Equal(t, 0.220, buffer.Root.Left.Priority)
+			assert.Equal(t, 20.20, buffer.Root.Right.Value)
+			assert.Equal(t, 0.300, buffer.Root.Right.Priority)
+			assert.Equal(t, 10.10, buffer.Root.Left.Left.Value)
+			assert.Equal(t, 0.210, buffer.Root.Left.Left.Priority)
+			assert.Nil(t, buffer.Root.Left.Right)
+			assert.Equal(t, 30.30, buffer.Root.Right.Right.Value)
+			assert.Equal(t, 0.200, buffer.Root.Right.Right.Priority)
+			assert.Nil(t, buffer.Root.Right.Left)
+			assert.Equal(t, 40.40, buffer.Root.Right.Right.Right.Value)
+			assert.Equal(t, 0.100, buffer.Root.Right.Right.Right.Priority)
 		})
 	})
 
@@ -271,83 +273,83 @@ func TestInsert(t *testing.T) {
 		buffer := newTreapBuffer(stringTestComparator)
 
 		t.Run("OnEmpty", func(t *testing.T) {
-			buffer.insert(newNode("30", 0.200))
-			assert.Equal(t, 1, buffer.currentSize)
-			assert.Equal(t, "30", buffer.root.value)
-			assert.Equal(t, 0.200, buffer.root.priority)
+			buffer.Insert(newNode("30", 0.200))
+			assert.Equal(t, 1, buffer.Size)
+			assert.Equal(t, "30", buffer.Root.Value)
+			assert.Equal(t, 0.200, buffer.Root.Priority)
 		})
 
 		t.Run("NewRightLeaf", func(t *testing.T) {
-			buffer.insert(newNode("40", 0.100))
-			assert.Equal(t, 2, buffer.currentSize)
-			assert.Equal(t, "30", buffer.root.value)
-			assert.Equal(t, 0.200, buffer.root.priority)
-			assert.Nil(t, buffer.root.left)
-			assert.Equal(t, "40", buffer.root.right.value)
-			assert.Equal(t, 0.100, buffer.root.right.priority)
+			buffer.Insert(newNode("40", 0.100))
+			assert.Equal(t, 2, buffer.Size)
+			assert.Equal(t, "30", buffer.Root.Value)
+			assert.Equal(t, 0.200, buffer.Root.Priority)
+			assert.Nil(t, buffer.Root.Left)
+			assert.Equal(t, "40", buffer.Root.Right.Value)
+			assert.Equal(t, 0.100, buffer.Root.Right.Priority)
 		})
 
 		t.Run("NewRoot", func(t *testing.T) {
-			buffer.insert(newNode("20", 0.300))
-			assert.Equal(t, 3, buffer.currentSize)
-			assert.Equal(t, "20", buffer.root.value)
-			assert.Equal(t, 0.300, buffer.root.priority)
-			assert.Nil(t, buffer.root.left)
-			assert.Equal(t, "30", buffer.root.right.value)
-			assert.Equal(t, 0.200, buffer.root.right.priority)
-			assert.Nil(t, buffer.root.right.left)
-			assert.Equal(t, "40", buffer.root.right.right.value)
-			assert.Equal(t, 0.100, buffer.root.right.right.priority)
+			buffer.Insert(newNode("20", 0.300))
+			assert.Equal(t, 3, buffer.Size)
+			assert.Equal(t, "20", buffer.Root.Value)
+			assert.Equal(t, 0.300, buffer.Root.Priority)
+			assert.Nil(t, buffer.Root.Left)
+			assert.Equal(t, "30", buffer.Root.Right.Value)
+			assert.Equal(t, 0.200, buffer.Root.Right.Priority)
+			assert.Nil(t, buffer.Root.Right.Left)
+			assert.Equal(t, "40", buffer.Root.Right.Right.Value)
+			assert.Equal(t, 0.100, buffer.Root.Right.Right.Priority)
 		})
 
 		t.Run("NewLeftLeaf", func(t *testing.T) {
-			buffer.insert(newNode("10", 0.210))
-			assert.Equal(t, 4, buffer.currentSize)
-			assert.Equal(t, "20", buffer.root.value)
-			assert.Equal(t, 0.300, buffer.root.priority)
-			assert.Equal(t, "10", buffer.root.left.value)
-			assert.Equal(t, 0.210, buffer.root.left.priority)
-			assert.Equal(t, "30", buffer.root.right.value)
-			assert.Equal(t, 0.200, buffer.root.right.priority)
-			assert.Nil(t, buffer.root.right.left)
-			assert.Equal(t, "40", buffer.root.right.right.value)
-			assert.Equal(t, 0.100, buffer.root.right.right.priority)
+			buffer.Insert(newNode("10", 0.210))
+			assert.Equal(t, 4, buffer.Size)
+			assert.Equal(t, "20", buffer.Root.Value)
+			assert.Equal(t, 0.300, buffer.Root.Priority)
+			assert.Equal(t, "10", buffer.Root.Left.Value)
+			assert.Equal(t, 0.210, buffer.Root.Left.Priority)
+			assert.Equal(t, "30", buffer.Root.Right.Value)
+			assert.Equal(t, 0.200, buffer.Root.Right.Priority)
+			assert.Nil(t, buffer.Root.Right.Left)
+			assert.Equal(t, "40", buffer.Root.Right.Right.Value)
+			assert.Equal(t, 0.100, buffer.Root.Right.Right.Priority)
 		})
 
 		t.Run("MiddleWithRotate", func(t *testing.T) {
-			buffer.insert(newNode("15", 0.220))
-			assert.Equal(t, 5, buffer.currentSize)
-			assert.Equal(t, "20", buffer.root.value)
-			assert.Equal(t, 0.300, buffer.root.priority)
-			assert.Equal(t, "15", buffer.root.left.value)
-			assert.Equal(t, 0.220, buffer.root.left.priority)
-			assert.Equal(t, "10", buffer.root.left.left.value)
-			assert.Equal(t, 0.210, buffer.root.left.left.priority)
-			assert.Nil(t, buffer.root.left.right)
-			assert.Equal(t, "30", buffer.root.right.value)
-			assert.Equal(t, 0.200, buffer.root.right.priority)
-			assert.Nil(t, buffer.root.right.left)
-			assert.Equal(t, "40", buffer.root.right.right.value)
-			assert.Equal(t, 0.100, buffer.root.right.right.priority)
+			buffer.Insert(newNode("15", 0.220))
+			assert.Equal(t, 5, buffer.Size)
+			assert.Equal(t, "20", buffer.Root.Value)
+			assert.Equal(t, 0.300, buffer.Root.Priority)
+			assert.Equal(t, "15", buffer.Root.Left.Value)
+			assert.Equal(t, 0.220, buffer.Root.Left.Priority)
+			assert.Equal(t, "10", buffer.Root.Left.Left.Value)
+			assert.Equal(t, 0.210, buffer.Root.Left.Left.Priority)
+			assert.Nil(t, buffer.Root.Left.Right)
+			assert.Equal(t, "30", buffer.Root.Right.Value)
+			assert.Equal(t, 0.200, buffer.Root.Right.Priority)
+			assert.Nil(t, buffer.Root.Right.Left)
+			assert.Equal(t, "40", buffer.Root.Right.Right.Value)
+			assert.Equal(t, 0.100, buffer.Root.Right.Right.Priority)
 		})
 
 		t.Run("NewRootLowerValue", func(t *testing.T) {
-			buffer.insert(newNode("18", 0.310))
-			assert.Equal(t, 6, buffer.currentSize)
-			assert.Equal(t, "18", buffer.root.value)
-			assert.Equal(t, 0.310, buffer.root.priority)
-			assert.Equal(t, "15", buffer.root.left.value)
-			assert.Equal(t, 0.220, buffer.root.left.priority)
-			assert.Equal(t, "20", buffer.root.right.value)
-			assert.Equal(t, 0.300, buffer.root.right.priority)
-			assert.Equal(t, "10", buffer.root.left.left.value)
-			assert.Equal(t, 0.210, buffer.root.left.left.priority)
-			assert.Nil(t, buffer.root.left.right)
-			assert.Equal(t, "30", buffer.root.right.right.value)
-			assert.Equal(t, 0.200, buffer.root.right.right.priority)
-			assert.Nil(t, buffer.root.right.left)
-			assert.Equal(t, "40", buffer.root.right.right.right.value)
-			assert.Equal(t, 0.100, buffer.root.right.right.right.priority)
+			buffer.Insert(newNode("18", 0.310))
+			assert.Equal(t, 6, buffer.Size)
+			assert.Equal(t, "18", buffer.Root.Value)
+			assert.Equal(t, 0.310, buffer.Root.Priority)
+			assert.Equal(t, "15", buffer.Root.Left.Value)
+			assert.Equal(t, 0.220, buffer.Root.Left.Priority)
+			assert.Equal(t, "20", buffer.Root.Right.Value)
+			assert.Equal(t, 0.300, buffer.Root.Right.Priority)
+			assert.Equal(t, "10", buffer.Root.Left.Left.Value)
+			assert.Equal(t, 0.210, buffer.Root.Left.Left.Priority)
+			assert.Nil(t, buffer.Root.Left.Right)
+			assert.Equal(t, "30", buffer.Root.Right.Right.Value)
+			assert.Equal(t, 0.200, buffer.Root.Right.Right.Priority)
+			assert.Nil(t, buffer.Root.Right.Left)
+			assert.Equal(t, "40", buffer.Root.Right.Right.Right.Value)
+			assert.Equal(t, 0.100, buffer.Root.Right.Right.Right.Priority)
 		})
 	})
 
@@ -355,104 +357,104 @@ func TestInsert(t *testing.T) {
 		buffer := newTreapBuffer(structTestComparator)
 
 		t.Run("OnEmpty", func(t *testing.T) {
-			buffer.insert(newNode(&testStruct{id: 30, name: "Bruce"}, 0.200))
-			assert.Equal(t, 1, buffer.currentSize)
-			assert.Equal(t, 30, buffer.root.value.id)
-			assert.Equal(t, "Bruce", buffer.root.value.name)
-			assert.Equal(t, 0.200, buffer.root.priority)
+			buffer.Insert(newNode(&testStruct{id: 30, name: "Bruce"}, 0.200))
+			assert.Equal(t, 1, buffer.Size)
+			assert.Equal(t, 30, buffer.Root.Value.id)
+			assert.Equal(t, "Bruce", buffer.Root.Value.name)
+			assert.Equal(t, 0.200, buffer.Root.Priority)
 		})
 
 		t.Run("NewRightLeaf", func(t *testing.T) {
-			buffer.insert(newNode(&testStruct{id: 40, name: "Clark"}, 0.100))
-			assert.Equal(t, 2, buffer.currentSize)
-			assert.Equal(t, 30, buffer.root.value.id)
-			assert.Equal(t, "Bruce", buffer.root.value.name)
-			assert.Equal(t, 0.200, buffer.root.priority)
-			assert.Nil(t, buffer.root.left)
-			assert.Equal(t, 40, buffer.root.right.value.id)
-			assert.Equal(t, "Clark", buffer.root.right.value.name)
-			assert.Equal(t, 0.100, buffer.root.right.priority)
+			buffer.Insert(newNode(&testStruct{id: 40, name: "Clark"}, 0.100))
+			assert.Equal(t, 2, buffer.Size)
+			assert.Equal(t, 30, buffer.Root.Value.id)
+			assert.Equal(t, "Bruce", buffer.Root.Value.name)
+			assert.Equal(t, 0.200, buffer.Root.Priority)
+			assert.Nil(t, buffer.Root.Left)
+			assert.Equal(t, 40, buffer.Root.Right.Value.id)
+			assert.Equal(t, "Clark", buffer.Root.Right.Value.name)
+			assert.Equal(t, 0.100, buffer.Root.Right.Priority)
 		})
 
 		t.Run("NewRoot", func(t *testing.T) {
-			buffer.insert(newNode(&testStruct{id: 20, name: "Selina"}, 0.300))
-			assert.Equal(t, 3, buffer.currentSize)
-			assert.Equal(t, 20, buffer.root.value.id)
-			assert.Equal(t, "Selina", buffer.root.value.name)
-			assert.Equal(t, 0.300, buffer.root.priority)
-			assert.Nil(t, buffer.root.left)
-			assert.Equal(t, 30, buffer.root.right.value.id)
-			assert.Equal(t, "Bruce", buffer.root.right.value.name)
-			assert.Equal(t, 0.200, buffer.root.right.priority)
-			assert.Nil(t, buffer.root.right.left)
-			assert.Equal(t, 40, buffer.root.right.right.value.id)
-			assert.Equal(t, "Clark", buffer.root.right.right.value.name)
-			assert.Equal(t, 0.100, buffer.root.right.right.priority)
+			buffer.Insert(newNode(&testStruct{id: 20, name: "Selina"}, 0.300))
+			assert.Equal(t, 3, buffer.Size)
+			assert.Equal(t, 20, buffer.Root.Value.id)
+			assert.Equal(t, "Selina", buffer.Root.Value.name)
+			assert.Equal(t, 0.300, buffer.Root.Priority)
+			assert.Nil(t, buffer.Root.Left)
+			assert.Equal(t, 30, buffer.Root.Right.Value.id)
+			assert.Equal(t, "Bruce", buffer.Root.Right.Value.name)
+			assert.Equal(t, 0.200, buffer.Root.Right.Priority)
+			assert.Nil(t, buffer.Root.Right.Left)
+			assert.Equal(t, 40, buffer.Root.Right.Right.Value.id)
+			assert.Equal(t, "Clark", buffer.Root.Right.Right.Value.name)
+			assert.Equal(t, 0.100, buffer.Root.Right.Right.Priority)
 		})
 
 		t.Run("NewLeftLeaf", func(t *testing.T) {
-			buffer.insert(newNode(&testStruct{id: 10, name: "Pamela"}, 0.210))
-			assert.Equal(t, 4, buffer.currentSize)
-			assert.Equal(t, 20, buffer.root.value.id)
-			assert.Equal(t, "Selina", buffer.root.value.name)
-			assert.Equal(t, 0.300, buffer.root.priority)
-			assert.Equal(t, 10, buffer.root.left.value.id)
-			assert.Equal(t, "Pamela", buffer.root.left.value.name)
-			assert.Equal(t, 0.210, buffer.root.left.priority)
-			assert.Equal(t, 30, buffer.root.right.value.id)
-			assert.Equal(t, "Bruce", buffer.root.right.value.name)
-			assert.Equal(t, 0.200, buffer.root.right.priority)
-			assert.Nil(t, buffer.root.right.left)
-			assert.Equal(t, 40, buffer.root.right.right.value.id)
-			assert.Equal(t, "Clark", buffer.root.right.right.value.name)
-			assert.Equal(t, 0.100, buffer.root.right.right.priority)
+			buffer.Insert(newNode(&testStruct{id: 10, name: "Pamela"}, 0.210))
+			assert.Equal(t, 4, buffer.Size)
+			assert.Equal(t, 20, buffer.Root.Value.id)
+			assert.Equal(t, "Selina", buffer.Root.Value.name)
+			assert.Equal(t, 0.300, buffer.Root.Priority)
+			assert.Equal(t, 10, buffer.Root.Left.Value.id)
+			assert.Equal(t, "Pamela", buffer.Root.Left.Value.name)
+			assert.Equal(t, 0.210, buffer.Root.Left.Priority)
+			assert.Equal(t, 30, buffer.Root.Right.Value.id)
+			assert.Equal(t, "Bruce", buffer.Root.Right.Value.name)
+			assert.Equal(t, 0.200, buffer.Root.Right.Priority)
+			assert.Nil(t, buffer.Root.Right.Left)
+			assert.Equal(t, 40, buffer.Root.Right.Right.Value.id)
+			assert.Equal(t, "Clark", buffer.Root.Right.Right.Value.name)
+			assert.Equal(t, 0.100, buffer.Root.Right.Right.Priority)
 		})
 
 		t.Run("MiddleWithRotate", func(t *testing.T) {
-			buffer.insert(newNode(&testStruct{id: 15, name: "Lex"}, 0.220))
-			assert.Equal(t, 5, buffer.currentSize)
-			assert.Equal(t, 20, buffer.root.value.id)
-			assert.Equal(t, "Selina", buffer.root.value.name)
-			assert.Equal(t, 0.300, buffer.root.priority)
-			assert.Equal(t, 15, buffer.root.left.value.id)
-			assert.Equal(t, "Lex", buffer.root.left.value.name)
-			assert.Equal(t, 0.220, buffer.root.left.priority)
-			assert.Equal(t, 10, buffer.root.left.left.value.id)
-			assert.Equal(t, "Pamela", buffer.root.left.left.value.name)
-			assert.Equal(t, 0.210, buffer.root.left.left.priority)
-			assert.Nil(t, buffer.root.left.right)
-			assert.Equal(t, 30, buffer.root.right.value.id)
-			assert.Equal(t, "Bruce", buffer.root.right.value.name)
-			assert.Equal(t, 0.200, buffer.root.right.priority)
-			assert.Nil(t, buffer.root.right.left)
-			assert.Equal(t, 40, buffer.root.right.right.value.id)
-			assert.Equal(t, "Clark", buffer.root.right.right.value.name)
-			assert.Equal(t, 0.100, buffer.root.right.right.priority)
+			buffer.Insert(newNode(&testStruct{id: 15, name: "Lex"}, 0.220))
+			assert.Equal(t, 5, buffer.Size)
+			assert.Equal(t, 20, buffer.Root.Value.id)
+			assert.Equal(t, "Selina", buffer.Root.Value.name)
+			assert.Equal(t, 0.300, buffer.Root.Priority)
+			assert.Equal(t, 15, buffer.Root.Left.Value.id)
+			assert.Equal(t, "Lex", buffer.Root.Left.Value.name)
+			assert.Equal(t, 0.220, buffer.Root.Left.Priority)
+			assert.Equal(t, 10, buffer.Root.Left.Left.Value.id)
+			assert.Equal(t, "Pamela", buffer.Root.Left.Left.Value.name)
+			assert.Equal(t, 0.210, buffer.Root.Left.Left.Priority)
+			assert.Nil(t, buffer.Root.Left.Right)
+			assert.Equal(t, 30, buffer.Root.Right.Value.id)
+			assert.Equal(t, "Bruce", buffer.Root.Right.Value.name)
+			assert.Equal(t, 0.200, buffer.Root.Right.Priority)
+			assert.Nil(t, buffer.Root.Right.Left)
+			assert.Equal(t, 40, buffer.Root.Right.Right.Value.id)
+			assert.Equal(t, "Clark", buffer.Root.Right.Right.Value.name)
+			assert.Equal(t, 0.100, buffer.Root.Right.Right.Priority)
 		})
 
 		t.Run("NewRootLowerValue", func(t *testing.T) {
-			buffer.insert(newNode(&testStruct{id: 18, name: "Hal"}, 0.310))
-			assert.Equal(t, 6, buffer.currentSize)
-			assert.Equal(t, 18, buffer.root.value.id)
-			assert.Equal(t, "Hal", buffer.root.value.name)
-			assert.Equal(t, 0.310, buffer.root.priority)
-			assert.Equal(t, 15, buffer.root.left.value.id)
-			assert.Equal(t, "Lex", buffer.root.left.value.name)
-			assert.Equal(t, 0.220, buffer.root.left.priority)
-			assert.Equal(t, 20, buffer.root.right.value.id)
-			assert.Equal(t, "Selina", buffer.root.right.value.name)
-			assert.Equal(t, 0.300, buffer.root.right.priority)
-			assert.Equal(t, 10, buffer.root.left.left.value.id)
-			assert.Equal(t, "Pamela", buffer.root.left.left.value.name)
-			assert.Equal(t, 0.210, buffer.root.left.left.priority)
-			assert.Nil(t, buffer.root.left.right)
-			assert.Equal(t, 30, buffer.root.right.right.value.id)
-			assert.Equal(t, "Bruce", buffer.root.right.right.value.name)
-			assert.Equal(t, 0.200, buffer.root.right.right.priority)
-			assert.Nil(t, buffer.root.right.left)
-			assert.Equal(t, 40, buffer.root.right.right.right.value.id)
-			assert.Equal(t, "Clark", buffer.root.right.right.right.value.name)
-			assert.Equal(t, 0.100, buffer.root.right.right.right.priority)
+			buffer.Insert(newNode(&testStruct{id: 18, name: "Hal"}, 0.310))
+			assert.Equal(t, 6, buffer.Size)
+			assert.Equal(t, 18, buffer.Root.Value.id)
+			assert.Equal(t, "Hal", buffer.Root.Value.name)
+			assert.Equal(t, 0.310, buffer.Root.Priority)
+			assert.Equal(t, 15, buffer.Root.Left.Value.id)
+			assert.Equal(t, "Lex", buffer.Root.Left.Value.name)
+			assert.Equal(t, 0.220, buffer.Root.Left.Priority)
+			assert.Equal(t, 20, buffer.Root.Right.Value.id)
+			assert.Equal(t, "Selina", buffer.Root.Right.Value.name)
+			assert.Equal(t, 0.300, buffer.Root.Right.Priority)
+			assert.Equal(t, 10, buffer.Root.Left.Left.Value.id)
+			assert.Equal(t, "Pamela", buffer.Root.Left.Left.Value.name)
+			assert.Equal(t, 0.210, buffer.Root.Left.Left.Priority)
+			assert.Nil(t, buffer.Root.Left.Right)
+			assert.Equal(t, 30, buffer.Root.Right.Right.Value.id)
+			assert.Equal(t, "Bruce", buffer.Root.Right.Right.Value.name)
+			assert.Equal(t, 0.200, buffer.Root.Right.Right.Priority)
+			assert.Nil(t, buffer.Root.Right.Left)
+			assert.Equal(t, 40, buffer.Root.Right.Right.Right.Value.id)
+			assert.Equal(t, "Clark", buffer.Root.Right.Right.Right.Value.name)
+			assert.Equal(t, 0.100, buffer.Root.Right.Right.Right.Priority)
 		})
 	})
 }
@@ -466,7 +468,7 @@ func BenchmarkInsert(b *testing.B) {
 				buffer := newTreapBuffer(intTestComparator)
 				b.ResetTimer()
 				for _, element := range stream {
-					buffer.insert(newNode(element, rand.Float64()))
+					buffer.Insert(newNode(element, rand.Float64()))
 				}
 			})
 		}
@@ -480,7 +482,7 @@ func BenchmarkInsert(b *testing.B) {
 				buffer := newTreapBuffer(floatTestComparator)
 				b.ResetTimer()
 				for _, element := range stream {
-					buffer.insert(newNode(element, rand.Float64()))
+					buffer.Insert(newNode(element, rand.Float64()))
 				}
 			})
 		}
@@ -494,7 +496,7 @@ func BenchmarkInsert(b *testing.B) {
 				buffer := newTreapBuffer(stringTestComparator)
 				b.ResetTimer()
 				for _, element := range stream {
-					buffer.insert(newNode(element, rand.Float64()))
+					buffer.Insert(newNode(element, rand.Float64()))
 				}
 			})
 		}
@@ -508,7 +510,7 @@ func BenchmarkInsert(b *testing.B) {
 				buffer := newTreapBuffer(structTestComparator)
 				b.ResetTimer()
 				for _, element := range stream {
-					buffer.insert(newNode(element, rand.Float64()))
+					buffer.Insert(newNode(element, rand.Float64()))
 				}
 			})
 		}
@@ -518,311 +520,311 @@ func BenchmarkInsert(b *testing.B) {
 func TestInsertOverwrite(t *testing.T) {
 	t.Run("Int", func(t *testing.T) {
 		buffer := newTreapBuffer(intTestComparator)
-		buffer.insert(newNode(30, 0.200))
-		buffer.insert(newNode(40, 0.100))
-		buffer.insert(newNode(20, 0.300))
-		buffer.insert(newNode(10, 0.210))
-		buffer.insert(newNode(15, 0.220))
-		buffer.insert(newNode(18, 0.310))
+		buffer.Insert(newNode(30, 0.200))
+		buffer.Insert(newNode(40, 0.100))
+		buffer.Insert(newNode(20, 0.300))
+		buffer.Insert(newNode(10, 0.210))
+		buffer.Insert(newNode(15, 0.220))
+		buffer.Insert(newNode(18, 0.310))
 
 		newPriority1 := 0.230
 		t.Run("InTheMiddle", func(t *testing.T) {
-			buffer.insert(newNode(10, newPriority1))
-			assert.Equal(t, 6, buffer.currentSize)
-			assert.Equal(t, 18, buffer.root.value)
-			assert.Equal(t, 0.310, buffer.root.priority)
-			assert.Equal(t, 10, buffer.root.left.value)
-			assert.Equal(t, newPriority1, buffer.root.left.priority)
-			assert.Equal(t, 20, buffer.root.right.value)
-			assert.Equal(t, 0.300, buffer.root.right.priority)
-			assert.Nil(t, buffer.root.left.left)
-			assert.Equal(t, 15, buffer.root.left.right.value)
-			assert.Equal(t, 0.220, buffer.root.left.right.priority)
-			assert.Equal(t, 30, buffer.root.right.right.value)
-			assert.Equal(t, 0.200, buffer.root.right.right.priority)
-			assert.Nil(t, buffer.root.right.left)
-			assert.Equal(t, 40, buffer.root.right.right.right.value)
-			assert.Equal(t, 0.100, buffer.root.right.right.right.priority)
+			buffer.Insert(newNode(10, newPriority1))
+			assert.Equal(t, 6, buffer.Size)
+			assert.Equal(t, 18, buffer.Root.Value)
+			assert.Equal(t, 0.310, buffer.Root.Priority)
+			assert.Equal(t, 10, buffer.Root.Left.Value)
+			assert.Equal(t, newPriority1, buffer.Root.Left.Priority)
+			assert.Equal(t, 20, buffer.Root.Right.Value)
+			assert.Equal(t, 0.300, buffer.Root.Right.Priority)
+			assert.Nil(t, buffer.Root.Left.Left)
+			assert.Equal(t, 15, buffer.Root.Left.Right.Value)
+			assert.Equal(t, 0.220, buffer.Root.Left.Right.Priority)
+			assert.Equal(t, 30, buffer.Root.Right.Right.Value)
+			assert.Equal(t, 0.200, buffer.Root.Right.Right.Priority)
+			assert.Nil(t, buffer.Root.Right.Left)
+			assert.Equal(t, 40, buffer.Root.Right.Right.Right.Value)
+			assert.Equal(t, 0.100, buffer.Root.Right.Right.Right.Priority)
 		})
 
 		newPriority2 := 0.350
 		t.Run("UpgradeToRoot", func(t *testing.T) {
-			buffer.insert(newNode(15, newPriority2))
-			assert.Equal(t, 6, buffer.currentSize)
-			assert.Equal(t, 15, buffer.root.value)
-			assert.Equal(t, newPriority2, buffer.root.priority)
-			assert.Equal(t, 10, buffer.root.left.value)
-			assert.Equal(t, newPriority1, buffer.root.left.priority)
-			assert.Equal(t, 18, buffer.root.right.value)
-			assert.Equal(t, 0.310, buffer.root.right.priority)
-			assert.Nil(t, buffer.root.right.left)
-			assert.Equal(t, 20, buffer.root.right.right.value)
-			assert.Equal(t, 0.300, buffer.root.right.right.priority)
-			assert.Nil(t, buffer.root.right.right.left)
-			assert.Equal(t, 30, buffer.root.right.right.right.value)
-			assert.Equal(t, 0.200, buffer.root.right.right.right.priority)
-			assert.Nil(t, buffer.root.right.right.right.left)
-			assert.Equal(t, 40, buffer.root.right.right.right.right.value)
-			assert.Equal(t, 0.100, buffer.root.right.right.right.right.priority)
+			buffer.Insert(newNode(15, newPriority2))
+			assert.Equal(t, 6, buffer.Size)
+			assert.Equal(t, 15, buffer.Root.Value)
+			assert.Equal(t, newPriority2, buffer.Root.Priority)
+			assert.Equal(t, 10, buffer.Root.Left.Value)
+			assert.Equal(t, newPriority1, buffer.Root.Left.Priority)
+			assert.Equal(t, 18, buffer.Root.Right.Value)
+			assert.Equal(t, 0.310, buffer.Root.Right.Priority)
+			assert.Nil(t, buffer.Root.Right.Left)
+			assert.Equal(t, 20, buffer.Root.Right.Right.Value)
+			assert.Equal(t, 0.300, buffer.Root.Right.Right.Priority)
+			assert.Nil(t, buffer.Root.Right.Right.Left)
+			assert.Equal(t, 30, buffer.Root.Right.Right.Right.Value)
+			assert.Equal(t, 0.200, buffer.Root.Right.Right.Right.Priority)
+			assert.Nil(t, buffer.Root.Right.Right.Right.Left)
+			assert.Equal(t, 40, buffer.Root.Right.Right.Right.Right.Value)
+			assert.Equal(t, 0.100, buffer.Root.Right.Right.Right.Right.Priority)
 		})
 
 		newPriority3 := 0.150
 		t.Run("DowngradeFromRoot", func(t *testing.T) {
-			buffer.insert(newNode(15, newPriority3))
-			assert.Equal(t, 6, buffer.currentSize)
-			assert.Equal(t, 18, buffer.root.value)
-			assert.Equal(t, 0.310, buffer.root.priority)
-			assert.Equal(t, 10, buffer.root.left.value)
-			assert.Equal(t, newPriority1, buffer.root.left.priority)
-			assert.Nil(t, buffer.root.left.left)
-			assert.Equal(t, 15, buffer.root.left.right.value)
-			assert.Equal(t, newPriority3, buffer.root.left.right.priority)
-			assert.Equal(t, 20, buffer.root.right.value)
-			assert.Equal(t, 0.300, buffer.root.right.priority)
-			assert.Nil(t, buffer.root.right.left)
-			assert.Equal(t, 30, buffer.root.right.right.value)
-			assert.Equal(t, 0.200, buffer.root.right.right.priority)
-			assert.Nil(t, buffer.root.right.right.left)
-			assert.Equal(t, 40, buffer.root.right.right.right.value)
-			assert.Equal(t, 0.100, buffer.root.right.right.right.priority)
-			assert.Nil(t, buffer.root.right.right.right.left)
+			buffer.Insert(newNode(15, newPriority3))
+			assert.Equal(t, 6, buffer.Size)
+			assert.Equal(t, 18, buffer.Root.Value)
+			assert.Equal(t, 0.310, buffer.Root.Priority)
+			assert.Equal(t, 10, buffer.Root.Left.Value)
+			assert.Equal(t, newPriority1, buffer.Root.Left.Priority)
+			assert.Nil(t, buffer.Root.Left.Left)
+			assert.Equal(t, 15, buffer.Root.Left.Right.Value)
+			assert.Equal(t, newPriority3, buffer.Root.Left.Right.Priority)
+			assert.Equal(t, 20, buffer.Root.Right.Value)
+			assert.Equal(t, 0.300, buffer.Root.Right.Priority)
+			assert.Nil(t, buffer.Root.Right.Left)
+			assert.Equal(t, 30, buffer.Root.Right.Right.Value)
+			assert.Equal(t, 0.200, buffer.Root.Right.Right.Priority)
+			assert.Nil(t, buffer.Root.Right.Right.Left)
+			assert.Equal(t, 40, buffer.Root.Right.Right.Right.Value)
+			assert.Equal(t, 0.100, buffer.Root.Right.Right.Right.Priority)
+			assert.Nil(t, buffer.Root.Right.Right.Right.Left)
 		})
 	})
 
 	t.Run("Float", func(t *testing.T) {
 		buffer := newTreapBuffer(floatTestComparator)
-		buffer.insert(newNode(30.30, 0.200))
-		buffer.insert(newNode(40.40, 0.100))
-		buffer.insert(newNode(20.20, 0.300))
-		buffer.insert(newNode(10.10, 0.210))
-		buffer.insert(newNode(15.15, 0.220))
-		buffer.insert(newNode(18.18, 0.310))
+		buffer.Insert(newNode(30.30, 0.200))
+		buffer.Insert(newNode(40.40, 0.100))
+		buffer.Insert(newNode(20.20, 0.300))
+		buffer.Insert(newNode(10.10, 0.210))
+		buffer.Insert(newNode(15.15, 0.220))
+		buffer.Insert(newNode(18.18, 0.310))
 
 		newPriority1 := 0.230
 		t.Run("InTheMiddle", func(t *testing.T) {
-			buffer.insert(newNode(10.10, newPriority1))
-			assert.Equal(t, 6, buffer.currentSize)
-			assert.Equal(t, 18.18, buffer.root.value)
-			assert.Equal(t, 0.310, buffer.root.priority)
-			assert.Equal(t, 10.10, buffer.root.left.value)
-			assert.Equal(t, newPriority1, buffer.root.left.priority)
-			assert.Equal(t, 20.20, buffer.root.right.value)
-			assert.Equal(t, 0.300, buffer.root.right.priority)
-			assert.Nil(t, buffer.root.left.left)
-			assert.Equal(t, 15.15, buffer.root.left.right.value)
-			assert.Equal(t, 0.220, buffer.root.left.right.priority)
-			assert.Equal(t, 30.30, buffer.root.right.right.value)
-			assert.Equal(t, 0.200, buffer.root.right.right.priority)
-			assert.Nil(t, buffer.root.right.left)
-			assert.Equal(t, 40.40, buffer.root.right.right.right.value)
-			assert.Equal(t, 0.100, buffer.root.right.right.right.priority)
+			buffer.Insert(newNode(10.10, newPriority1))
+			assert.Equal(t, 6, buffer.Size)
+			assert.Equal(t, 18.18, buffer.Root.Value)
+			assert.Equal(t, 0.310, buffer.Root.Priority)
+			assert.Equal(t, 10.10, buffer.Root.Left.Value)
+			assert.Equal(t, newPriority1, buffer.Root.Left.Priority)
+			assert.Equal(t, 20.20, buffer.Root.Right.Value)
+			assert.Equal(t, 0.300, buffer.Root.Right.Priority)
+			assert.Nil(t, buffer.Root.Left.Left)
+			assert.Equal(t, 15.15, buffer.Root.Left.Right.Value)
+			assert.Equal(t, 0.220, buffer.Root.Left.Right.Priority)
+			assert.Equal(t, 30.30, buffer.Root.Right.Right.Value)
+			assert.Equal(t, 0.200, buffer.Root.Right.Right.Priority)
+			assert.Nil(t, buffer.Root.Right.Left)
+			assert.Equal(t, 40.40, buffer.Root.Right.Right.Right.Value)
+			assert.Equal(t, 0.100, buffer.Root.Right.Right.Right.Priority)
 		})
 
 		newPriority2 := 0.350
 		t.Run("UpgradeToRoot", func(t *testing.T) {
-			buffer.insert(newNode(15.15, newPriority2))
-			assert.Equal(t, 6, buffer.currentSize)
-			assert.Equal(t, 15.15, buffer.root.value)
-			assert.Equal(t, newPriority2, buffer.root.priority)
-			assert.Equal(t, 10.10, buffer.root.left.value)
-			assert.Equal(t, newPriority1, buffer.root.left.priority)
-			assert.Equal(t, 18.18, buffer.root.right.value)
-			assert.Equal(t, 0.310, buffer.root.right.priority)
-			assert.Nil(t, buffer.root.right.left)
-			assert.Equal(t, 20.20, buffer.root.right.right.value)
-			assert.Equal(t, 0.300, buffer.root.right.right.priority)
-			assert.Nil(t, buffer.root.right.right.left)
-			assert.Equal(t, 30.30, buffer.root.right.right.right.value)
-			assert.Equal(t, 0.200, buffer.root.right.right.right.priority)
-			assert.Nil(t, buffer.root.right.right.right.left)
-			assert.Equal(t, 40.40, buffer.root.right.right.right.right.value)
-			assert.Equal(t, 0.100, buffer.root.right.right.right.right.priority)
+			buffer.Insert(newNode(15.15, newPriority2))
+			assert.Equal(t, 6, buffer.Size)
+			assert.Equal(t, 15.15, buffer.Root.Value)
+			assert.Equal(t, newPriority2, buffer.Root.Priority)
+			assert.Equal(t, 10.10, buffer.Root.Left.Value)
+			assert.Equal(t, newPriority1, buffer.Root.Left.Priority)
+			assert.Equal(t, 18.18, buffer.Root.Right.Value)
+			assert.Equal(t, 0.310, buffer.Root.Right.Priority)
+			assert.Nil(t, buffer.Root.Right.Left)
+			assert.Equal(t, 20.20, buffer.Root.Right.Right.Value)
+			assert.Equal(t, 0.300, buffer.Root.Right.Right.Priority)
+			assert.Nil(t, buffer.Root.Right.Right.Left)
+			assert.Equal(t, 30.30, buffer.Root.Right.Right.Right.Value)
+			assert.Equal(t, 0.200, buffer.Root.Right.Right.Right.Priority)
+			assert.Nil(t, buffer.Root.Right.Right.Right.Left)
+			assert.Equal(t, 40.40, buffer.Root.Right.Right.Right.Right.Value)
+			assert.Equal(t, 0.100, buffer.Root.Right.Right.Right.Right.Priority)
 		})
 
 		newPriority3 := 0.150
 		t.Run("DowngradeFromRoot", func(t *testing.T) {
-			buffer.insert(newNode(15.15, newPriority3))
-			assert.Equal(t, 6, buffer.currentSize)
-			assert.Equal(t, 18.18, buffer.root.value)
-			assert.Equal(t, 0.310, buffer.root.priority)
-			assert.Equal(t, 10.10, buffer.root.left.value)
-			assert.Equal(t, newPriority1, buffer.root.left.priority)
-			assert.Nil(t, buffer.root.left.left)
-			assert.Equal(t, 15.15, buffer.root.left.right.value)
-			assert.Equal(t, newPriority3, buffer.root.left.right.priority)
-			assert.Equal(t, 20.20, buffer.root.right.value)
-			assert.Equal(t, 0.300, buffer.root.right.priority)
-			assert.Nil(t, buffer.root.right.left)
-			assert.Equal(t, 30.30, buffer.root.right.right.value)
-			assert.Equal(t, 0.200, buffer.root.right.right.priority)
-			assert.Nil(t, buffer.root.right.right.left)
-			assert.Equal(t, 40.40, buffer.root.right.right.right.value)
-			assert.Equal(t, 0.100, buffer.root.right.right.right.priority)
-			assert.Nil(t, buffer.root.right.right.right.left)
+			buffer.Insert(newNode(15.15, newPriority3))
+			assert.Equal(t, 6, buffer.Size)
+			assert.Equal(t, 18.18, buffer.Root.Value)
+			assert.Equal(t, 0.310, buffer.Root.Priority)
+			assert.Equal(t, 10.10, buffer.Root.Left.Value)
+			assert.Equal(t, newPriority1, buffer.Root.Left.Priority)
+			assert.Nil(t, buffer.Root.Left.Left)
+			assert.Equal(t, 15.15, buffer.Root.Left.Right.Value)
+			assert.Equal(t, newPriority3, buffer.Root.Left.Right.Priority)
+			assert.Equal(t, 20.20, buffer.Root.Right.Value)
+			assert.Equal(t, 0.300, buffer.Root.Right.Priority)
+			assert.Nil(t, buffer.Root.Right.Left)
+			assert.Equal(t, 30.30, buffer.Root.Right.Right.Value)
+			assert.Equal(t, 0.200, buffer.Root.Right.Right.Priority)
+			assert.Nil(t, buffer.Root.Right.Right.Left)
+			assert.Equal(t, 40.40, buffer.Root.Right.Right.Right.Value)
+			assert.Equal(t, 0.100, buffer.Root.Right.Right.Right.Priority)
+			assert.Nil(t, buffer.Root.Right.Right.Right.Left)
 		})
 	})
 
 	t.Run("String", func(t *testing.T) {
 		buffer := newTreapBuffer(stringTestComparator)
-		buffer.insert(newNode("30", 0.200))
-		buffer.insert(newNode("40", 0.100))
-		buffer.insert(newNode("20", 0.300))
-		buffer.insert(newNode("10", 0.210))
-		buffer.insert(newNode("15", 0.220))
-		buffer.insert(newNode("18", 0.310))
+		buffer.Insert(newNode("30", 0.200))
+		buffer.Insert(newNode("40", 0.100))
+		buffer.Insert(newNode("20", 0.300))
+		buffer.Insert(newNode("10", 0.210))
+		buffer.Insert(newNode("15", 0.220))
+		buffer.Insert(newNode("18", 0.310))
 
 		newPriority1 := 0.230
 		t.Run("InTheMiddle", func(t *testing.T) {
-			buffer.insert(newNode("10", newPriority1))
-			assert.Equal(t, 6, buffer.currentSize)
-			assert.Equal(t, "18", buffer.root.value)
-			assert.Equal(t, 0.310, buffer.root.priority)
-			assert.Equal(t, "10", buffer.root.left.value)
-			assert.Equal(t, newPriority1, buffer.root.left.priority)
-			assert.Equal(t, "20", buffer.root.right.value)
-			assert.Equal(t, 0.300, buffer.root.right.priority)
-			assert.Nil(t, buffer.root.left.left)
-			assert.Equal(t, "15", buffer.root.left.right.value)
-			assert.Equal(t, 0.220, buffer.root.left.right.priority)
-			assert.Equal(t, "30", buffer.root.right.right.value)
-			assert.Equal(t, 0.200, buffer.root.right.right.priority)
-			assert.Nil(t, buffer.root.right.left)
-			assert.Equal(t, "40", buffer.root.right.right.right.value)
-			assert.Equal(t, 0.100, buffer.root.right.right.right.priority)
+			buffer.Insert(newNode("10", newPriority1))
+			assert.Equal(t, 6, buffer.Size)
+			assert.Equal(t, "18", buffer.Root.Value)
+			assert.Equal(t, 0.310, buffer.Root.Priority)
+			assert.Equal(t, "10", buffer.Root.Left.Value)
+			assert.Equal(t, newPriority1, buffer.Root.Left.Priority)
+			assert.Equal(t, "20", buffer.Root.Right.Value)
+			assert.Equal(t, 0.300, buffer.Root.Right.Priority)
+			assert.Nil(t, buffer.Root.Left.Left)
+			assert.Equal(t, "15", buffer.Root.Left.Right.Value)
+			assert.Equal(t, 0.220, buffer.Root.Left.Right.Priority)
+			assert.Equal(t, "30", buffer.Root.Right.Right.Value)
+			assert.Equal(t, 0.200, buffer.Root.Right.Right.Priority)
+			assert.Nil(t, buffer.Root.Right.Left)
+			assert.Equal(t, "40", buffer.Root.Right.Right.Right.Value)
+			assert.Equal(t, 0.100, buffer.Root.Right.Right.Right.Priority)
 		})
 
 		newPriority2 := 0.350
 		t.Run("UpgradeToRoot", func(t *testing.T) {
-			buffer.insert(newNode("15", newPriority2))
-			assert.Equal(t, 6, buffer.currentSize)
-			assert.Equal(t, "15", buffer.root.value)
-			assert.Equal(t, newPriority2, buffer.root.priority)
-			assert.Equal(t, "10", buffer.root.left.value)
-			assert.Equal(t, newPriority1, buffer.root.left.priority)
-			assert.Equal(t, "18", buffer.root.right.value)
-			assert.Equal(t, 0.310, buffer.root.right.priority)
-			assert.Nil(t, buffer.root.right.left)
-			assert.Equal(t, "20", buffer.root.right.right.value)
-			assert.Equal(t, 0.300, buffer.root.right.right.priority)
-			assert.Nil(t, buffer.root.right.right.left)
-			assert.Equal(t, "30", buffer.root.right.right.right.value)
-			assert.Equal(t, 0.200, buffer.root.right.right.right.priority)
-			assert.Nil(t, buffer.root.right.right.right.left)
-			assert.Equal(t, "40", buffer.root.right.right.right.right.value)
-			assert.Equal(t, 0.100, buffer.root.right.right.right.right.priority)
+			buffer.Insert(newNode("15", newPriority2))
+			assert.Equal(t, 6, buffer.Size)
+			assert.Equal(t, "15", buffer.Root.Value)
+			assert.Equal(t, newPriority2, buffer.Root.Priority)
+			assert.Equal(t, "10", buffer.Root.Left.Value)
+			assert.Equal(t, newPriority1, buffer.Root.Left.Priority)
+			assert.Equal(t, "18", buffer.Root.Right.Value)
+			assert.Equal(t, 0.310, buffer.Root.Right.Priority)
+			assert.Nil(t, buffer.Root.Right.Left)
+			assert.Equal(t, "20", buffer.Root.Right.Right.Value)
+			assert.Equal(t, 0.300, buffer.Root.Right.Right.Priority)
+			assert.Nil(t, buffer.Root.Right.Right.Left)
+			assert.Equal(t, "30", buffer.Root.Right.Right.Right.Value)
+			assert.Equal(t, 0.200, buffer.Root.Right.Right.Right.Priority)
+			assert.Nil(t, buffer.Root.Right.Right.Right.Left)
+			assert.Equal(t, "40", buffer.Root.Right.Right.Right.Right.Value)
+			assert.Equal(t, 0.100, buffer.Root.Right.Right.Right.Right.Priority)
 		})
 
 		newPriority3 := 0.150
 		t.Run("DowngradeFromRoot", func(t *testing.T) {
-			buffer.insert(newNode("15", newPriority3))
-			assert.Equal(t, 6, buffer.currentSize)
-			assert.Equal(t, "18", buffer.root.value)
-			assert.Equal(t, 0.310, buffer.root.priority)
-			assert.Equal(t, "10", buffer.root.left.value)
-			assert.Equal(t, newPriority1, buffer.root.left.priority)
-			assert.Nil(t, buffer.root.left.left)
-			assert.Equal(t, "15", buffer.root.left.right.value)
-			assert.Equal(t, newPriority3, buffer.root.left.right.priority)
-			assert.Equal(t, "20", buffer.root.right.value)
-			assert.Equal(t, 0.300, buffer.root.right.priority)
-			assert.Nil(t, buffer.root.right.left)
-			assert.Equal(t, "30", buffer.root.right.right.value)
-			assert.Equal(t, 0.200, buffer.root.right.right.priority)
-			assert.Nil(t, buffer.root.right.right.left)
-			assert.Equal(t, "40", buffer.root.right.right.right.value)
-			assert.Equal(t, 0.100, buffer.root.right.right.right.priority)
-			assert.Nil(t, buffer.root.right.right.right.left)
+			buffer.Insert(newNode("15", newPriority3))
+			assert.Equal(t, 6, buffer.Size)
+			assert.Equal(t, "18", buffer.Root.Value)
+			assert.Equal(t, 0.310, buffer.Root.Priority)
+			assert.Equal(t, "10", buffer.Root.Left.Value)
+			assert.Equal(t, newPriority1, buffer.Root.Left.Priority)
+			assert.Nil(t, buffer.Root.Left.Left)
+			assert.Equal(t, "15", buffer.Root.Left.Right.Value)
+			assert.Equal(t, newPriority3, buffer.Root.Left.Right.Priority)
+			assert.Equal(t, "20", buffer.Root.Right.Value)
+			assert.Equal(t, 0.300, buffer.Root.Right.Priority)
+			assert.Nil(t, buffer.Root.Right.Left)
+			assert.Equal(t, "30", buffer.Root.Right.Right.Value)
+			assert.Equal(t, 0.200, buffer.Root.Right.Right.Priority)
+			assert.Nil(t, buffer.Root.Right.Right.Left)
+			assert.Equal(t, "40", buffer.Root.Right.Right.Right.Value)
+			assert.Equal(t, 0.100, buffer.Root.Right.Right.Right.Priority)
+			assert.Nil(t, buffer.Root.Right.Right.Right.Left)
 		})
 	})
 
 	t.Run("Struct", func(t *testing.T) {
 		buffer := newTreapBuffer(structTestComparator)
-		buffer.insert(newNode(&testStruct{id: 30, name: "Bruce"}, 0.200))
-		buffer.insert(newNode(&testStruct{id: 40, name: "Clark"}, 0.100))
-		buffer.insert(newNode(&testStruct{id: 20, name: "Selina"}, 0.300))
-		buffer.insert(newNode(&testStruct{id: 10, name: "Pamela"}, 0.210))
-		buffer.insert(newNode(&testStruct{id: 15, name: "Lex"}, 0.220))
-		buffer.insert(newNode(&testStruct{id: 18, name: "Hal"}, 0.310))
+		buffer.Insert(newNode(&testStruct{id: 30, name: "Bruce"}, 0.200))
+		buffer.Insert(newNode(&testStruct{id: 40, name: "Clark"}, 0.100))
+		buffer.Insert(newNode(&testStruct{id: 20, name: "Selina"}, 0.300))
+		buffer.Insert(newNode(&testStruct{id: 10, name: "Pamela"}, 0.210))
+		buffer.Insert(newNode(&testStruct{id: 15, name: "Lex"}, 0.220))
+		buffer.Insert(newNode(&testStruct{id: 18, name: "Hal"}, 0.310))
 
 		newPriority1 := 0.230
 		t.Run("InTheMiddle", func(t *testing.T) {
-			buffer.insert(newNode(&testStruct{id: 10, name: "Pamela"}, newPriority1))
-			assert.Equal(t, 6, buffer.currentSize)
-			assert.Equal(t, 18, buffer.root.value.id)
-			assert.Equal(t, "Hal", buffer.root.value.name)
-			assert.Equal(t, 0.310, buffer.root.priority)
-			assert.Equal(t, 10, buffer.root.left.value.id)
-			assert.Equal(t, "Pamela", buffer.root.left.value.name)
-			assert.Equal(t, newPriority1, buffer.root.left.priority)
-			assert.Equal(t, 20, buffer.root.right.value.id)
-			assert.Equal(t, "Selina", buffer.root.right.value.name)
-			assert.Equal(t, 0.300, buffer.root.right.priority)
-			assert.Nil(t, buffer.root.left.left)
-			assert.Equal(t, 15, buffer.root.left.right.value.id)
-			assert.Equal(t, "Lex", buffer.root.left.right.value.name)
-			assert.Equal(t, 0.220, buffer.root.left.right.priority)
-			assert.Equal(t, 30, buffer.root.right.right.value.id)
-			assert.Equal(t, "Bruce", buffer.root.right.right.value.name)
-			assert.Equal(t, 0.200, buffer.root.right.right.priority)
-			assert.Nil(t, buffer.root.right.left)
-			assert.Equal(t, 40, buffer.root.right.right.right.value.id)
-			assert.Equal(t, "Clark", buffer.root.right.right.right.value.name)
-			assert.Equal(t, 0.100, buffer.root.right.right.right.priority)
+			buffer.Insert(newNode(&testStruct{id: 10, name: "Pamela"}, newPriority1))
+			assert.Equal(t, 6, buffer.Size)
+			assert.Equal(t, 18, buffer.Root.Value.id)
+			assert.Equal(t, "Hal", buffer.Root.Value.name)
+			assert.Equal(t, 0.310, buffer.Root.Priority)
+			assert.Equal(t, 10, buffer.Root.Left.Value.id)
+			assert.Equal(t, "Pamela", buffer.Root.Left.Value.name)
+			assert.Equal(t, newPriority1, buffer.Root.Left.Priority)
+			assert.Equal(t, 20, buffer.Root.Right.Value.id)
+			assert.Equal(t, "Selina", buffer.Root.Right.Value.name)
+			assert.Equal(t, 0.300, buffer.Root.Right.Priority)
+			assert.Nil(t, buffer.Root.Left.Left)
+			assert.Equal(t, 15, buffer.Root.Left.Right.Value.id)
+			assert.Equal(t, "Lex", buffer.Root.Left.Right.Value.name)
+			assert.Equal(t, 0.220, buffer.Root.Left.Right.Priority)
+			assert.Equal(t, 30, buffer.Root.Right.Right.Value.id)
+			assert.Equal(t, "Bruce", buffer.Root.Right.Right.Value.name)
+			assert.Equal(t, 0.200, buffer.Root.Right.Right.Priority)
+			assert.Nil(t, buffer.Root.Right.Left)
+			assert.Equal(t, 40, buffer.Root.Right.Right.Right.Value.id)
+			assert.Equal(t, "Clark", buffer.Root.Right.Right.Right.Value.name)
+			assert.Equal(t, 0.100, buffer.Root.Right.Right.Right.Priority)
 		})
 
 		newPriority2 := 0.350
 		t.Run("UpgradeToRoot", func(t *testing.T) {
-			buffer.insert(newNode(&testStruct{id: 15, name: "Lex"}, newPriority2))
-			assert.Equal(t, 6, buffer.currentSize)
-			assert.Equal(t, 15, buffer.root.value.id)
-			assert.Equal(t, "Lex", buffer.root.value.name)
-			assert.Equal(t, newPriority2, buffer.root.priority)
-			assert.Equal(t, 10, buffer.root.left.value.id)
-			assert.Equal(t, "Pamela", buffer.root.left.value.name)
-			assert.Equal(t, newPriority1, buffer.root.left.priority)
-			assert.Equal(t, 18, buffer.root.right.value.id)
-			assert.Equal(t, "Hal", buffer.root.right.value.name)
-			assert.Equal(t, 0.310, buffer.root.right.priority)
-			assert.Nil(t, buffer.root.right.left)
-			assert.Equal(t, 20, buffer.root.right.right.value.id)
-			assert.Equal(t, "Selina", buffer.root.right.right.value.name)
-			assert.Equal(t, 0.300, buffer.root.right.right.priority)
-			assert.Nil(t, buffer.root.right.right.left)
-			assert.Equal(t, 30, buffer.root.right.right.right.value.id)
-			assert.Equal(t, "Bruce", buffer.root.right.right.right.value.name)
-			assert.Equal(t, 0.200, buffer.root.right.right.right.priority)
-			assert.Nil(t, buffer.root.right.right.right.left)
-			assert.Equal(t, 40, buffer.root.right.right.right.right.value.id)
-			assert.Equal(t, "Clark", buffer.root.right.right.right.right.value.name)
-			assert.Equal(t, 0.100, buffer.root.right.right.right.right.priority)
+			buffer.Insert(newNode(&testStruct{id: 15, name: "Lex"}, newPriority2))
+			assert.Equal(t, 6, buffer.Size)
+			assert.Equal(t, 15, buffer.Root.Value.id)
+			assert.Equal(t, "Lex", buffer.Root.Value.name)
+			assert.Equal(t, newPriority2, buffer.Root.Priority)
+			assert.Equal(t, 10, buffer.Root.Left.Value.id)
+			assert.Equal(t, "Pamela", buffer.Root.Left.Value.name)
+			assert.Equal(t, newPriority1, buffer.Root.Left.Priority)
+			assert.Equal(t, 18, buffer.Root.Right.Value.id)
+			assert.Equal(t, "Hal", buffer.Root.Right.Value.name)
+			assert.Equal(t, 0.310, buffer.Root.Right.Priority)
+			assert.Nil(t, buffer.Root.Right.Left)
+			assert.Equal(t, 20, buffer.Root.Right.Right.Value.id)
+			assert.Equal(t, "Selina", buffer.Root.Right.Right.Value.name)
+			assert.Equal(t, 0.300, buffer.Root.Right.Right.Priority)
+			assert.Nil(t, buffer.Root.Right.Right.Left)
+			assert.Equal(t, 30, buffer.Root.Right.Right.Right.Value.id)
+			assert.Equal(t, "Bruce", buffer.Root.Right.Right.Right.Value.name)
+			assert.Equal(t, 0.200, buffer.Root.Right.Right.Right.Priority)
+			assert.Nil(t, buffer.Root.Right.Right.Right.Left)
+			assert.Equal(t, 40, buffer.Root.Right.Right.Right.Right.Value.id)
+			assert.Equal(t, "Clark", buffer.Root.Right.Right.Right.Right.Value.name)
+			assert.Equal(t, 0.100, buffer.Root.Right.Right.Right.Right.Priority)
 		})
 
 		newPriority3 := 0.150
 		t.Run("DowngradeFromRoot", func(t *testing.T) {
-			buffer.insert(newNode(&testStruct{id: 15, name: "Lex"}, newPriority3))
-			assert.Equal(t, 6, buffer.currentSize)
-			assert.Equal(t, 18, buffer.root.value.id)
-			assert.Equal(t, "Hal", buffer.root.value.name)
-			assert.Equal(t, 0.310, buffer.root.priority)
-			assert.Equal(t, 10, buffer.root.left.value.id)
-			assert.Equal(t, "Pamela", buffer.root.left.value.name)
-			assert.Equal(t, newPriority1, buffer.root.left.priority)
-			assert.Nil(t, buffer.root.left.left)
-			assert.Equal(t, 15, buffer.root.left.right.value.id)
-			assert.Equal(t, "Lex", buffer.root.left.right.value.name)
-			assert.Equal(t, newPriority3, buffer.root.left.right.priority)
-			assert.Equal(t, 20, buffer.root.right.value.id)
-			assert.Equal(t, "Selina", buffer.root.right.value.name)
-			assert.Equal(t, 0.300, buffer.root.right.priority)
-			assert.Nil(t, buffer.root.right.left)
-			assert.Equal(t, 30, buffer.root.right.right.value.id)
-			assert.Equal(t, "Bruce", buffer.root.right.right.value.name)
-			assert.Equal(t, 0.200, buffer.root.right.right.priority)
-			assert.Nil(t, buffer.root.right.right.left)
-			assert.Equal(t, 40, buffer.root.right.right.right.value.id)
-			assert.Equal(t, "Clark", buffer.root.right.right.right.value.name)
-			assert.Equal(t, 0.100, buffer.root.right.right.right.priority)
-			assert.Nil(t, buffer.root.right.right.right.left)
+			buffer.Insert(newNode(&testStruct{id: 15, name: "Lex"}, newPriority3))
+			assert.Equal(t, 6, buffer.Size)
+			assert.Equal(t, 18, buffer.Root.Value.id)
+			assert.Equal(t, "Hal", buffer.Root.Value.name)
+			assert.Equal(t, 0.310, buffer.Root.Priority)
+			assert.Equal(t, 10, buffer.Root.Left.Value.id)
+			assert.Equal(t, "Pamela", buffer.Root.Left.Value.name)
+			assert.Equal(t, newPriority1, buffer.Root.Left.Priority)
+			assert.Nil(t, buffer.Root.Left.Left)
+			assert.Equal(t, 15, buffer.Root.Left.Right.Value.id)
+			assert.Equal(t, "Lex", buffer.Root.Left.Right.Value.name)
+			assert.Equal(t, newPriority3, buffer.Root.Left.Right.Priority)
+			assert.Equal(t, 20, buffer.Root.Right.Value.id)
+			assert.Equal(t, "Selina", buffer.Root.Right.Value.name)
+			assert.Equal(t, 0.300, buffer.Root.Right.Priority)
+			assert.Nil(t, buffer.Root.Right.Left)
+			assert.Equal(t, 30, buffer.Root.Right.Right.Value.id)
+			assert.Equal(t, "Bruce", buffer.Root.Right.Right.Value.name)
+			assert.Equal(t, 0.200, buffer.Root.Right.Right.Priority)
+			assert.Nil(t, buffer.Root.Right.Right.Left)
+			assert.Equal(t, 40, buffer.Root.Right.Right.Right.Value.id)
+			assert.Equal(t, "Clark", buffer.Root.Right.Right.Right.Value.name)
+			assert.Equal(t, 0.100, buffer.Root.Right.Right.Right.Priority)
+			assert.Nil(t, buffer.Root.Right.Right.Right.Left)
 		})
 	})
 }
@@ -835,11 +837,11 @@ func BenchmarkInsertOverwrite(b *testing.B) {
 				stream := newTestIntStream(length, length/100)
 				buffer := newTreapBuffer(intTestComparator)
 				for i := 0; i < length/100; i++ {
-					buffer.insert(newNode(stream[i], rand.Float64()))
+					buffer.Insert(newNode(stream[i], rand.Float64()))
 				}
 				b.ResetTimer()
 				for _, element := range stream {
-					buffer.insert(newNode(element, rand.Float64()))
+					buffer.Insert(newNode(element, rand.Float64()))
 				}
 			})
 		}
@@ -852,11 +854,11 @@ func BenchmarkInsertOverwrite(b *testing.B) {
 				stream := newTestFloatStream(length, length/100)
 				buffer := newTreapBuffer(floatTestComparator)
 				for i := 0; i < length/100; i++ {
-					buffer.insert(newNode(stream[i], rand.Float64()))
+					buffer.Insert(newNode(stream[i], rand.Float64()))
 				}
 				b.ResetTimer()
 				for _, element := range stream {
-					buffer.insert(newNode(element, rand.Float64()))
+					buffer.Insert(newNode(element, rand.Float64()))
 				}
 			})
 		}
@@ -869,11 +871,11 @@ func BenchmarkInsertOverwrite(b *testing.B) {
 				stream := newTestStringStream(length, length/100)
 				buffer := newTreapBuffer(stringTestComparator)
 				for i := 0; i < length/100; i++ {
-					buffer.insert(newNode(stream[i], rand.Float64()))
+					buffer.Insert(newNode(stream[i], rand.Float64()))
 				}
 				b.ResetTimer()
 				for _, element := range stream {
-					buffer.insert(newNode(element, rand.Float64()))
+					buffer.Insert(newNode(element, rand.Float64()))
 				}
 			})
 		}
@@ -886,11 +888,11 @@ func BenchmarkInsertOverwrite(b *testing.B) {
 				stream := newTestStructStream(length, length/100)
 				buffer := newTreapBuffer(structTestComparator)
 				for i := 0; i < length/100; i++ {
-					buffer.insert(newNode(stream[i], rand.Float64()))
+					buffer.Insert(newNode(stream[i], rand.Float64()))
 				}
 				b.ResetTimer()
 				for _, element := range stream {
-					buffer.insert(newNode(element, rand.Float64()))
+					buffer.Insert(newNode(element, rand.Float64()))
 				}
 			})
 		}
@@ -900,391 +902,399 @@ func BenchmarkInsertOverwrite(b *testing.B) {
 func TestDelete(t *testing.T) {
 	t.Run("Int", func(t *testing.T) {
 		buffer := newTreapBuffer(intTestComparator)
-		buffer.insert(newNode(30, 0.200))
-		buffer.insert(newNode(40, 0.100))
-		buffer.insert(newNode(20, 0.300))
-		buffer.insert(newNode(10, 0.210))
-		buffer.insert(newNode(15, 0.220))
-		buffer.insert(newNode(18, 0.310))
+		buffer.Insert(newNode(30, 0.200))
+		buffer.Insert(newNode(40, 0.100))
+		buffer.Insert(newNode(20, 0.300))
+		buffer.Insert(newNode(10, 0.210))
+		buffer.Insert(newNode(15, 0.220))
+		buffer.Insert(newNode(18, 0.310))
 
 		t.Run("RightLeaf", func(t *testing.T) {
-			buffer.delete(40)
-			assert.Equal(t, 5, buffer.currentSize)
-			assert.Equal(t, 18, buffer.root.value)
-			assert.Equal(t, 0.310, buffer.root.priority)
-			assert.Equal(t, 15, buffer.root.left.value)
-			assert.Equal(t, 0.220, buffer.root.left.priority)
-			assert.Equal(t, 20, buffer.root.right.value)
-			assert.Equal(t, 0.300, buffer.root.right.priority)
-			assert.Equal(t, 10, buffer.root.left.left.value)
-			assert.Equal(t, 0.210, buffer.root.left.left.priority)
-			assert.Nil(t, buffer.root.left.right)
-			assert.Equal(t, 30, buffer.root.right.right.value)
-			assert.Equal(t, 0.200, buffer.root.right.right.priority)
-			assert.Nil(t, buffer.root.right.left)
-			assert.Nil(t, buffer.root.right.right.right)
+			buffer.Delete(40)
+			assert.Equal(t, 5, buffer.Size)
+			assert.Equal(t, 18, buffer.Root.Value)
+			assert.Equal(t, 0.310, buffer.Root.Priority)
+			assert.Equal(t, 15, buffer.Root.Left.Value)
+			assert.Equal(t, 0.220, buffer.Root.Left.Priority)
+			assert.Equal(t, 20, buffer.Root.Right.Value)
+			assert.Equal(t, 0.300, buffer.Root.Right.Priority)
+			assert.Equal(t, 10, buffer.Root.Left.Left.Value)
+			assert.Equal(t, 0.210, buffer.Root.Left.Left.Priority)
+			assert.Nil(t, buffer.Root.Left.Right)
+			assert.Equal(t, 30, buffer.Root.Right.Right.Value)
+			assert.Equal(t, 0.200, buffer.Root.Right.Right.Priority)
+			assert.Nil(t, buffer.Root.Right.Left)
+			assert.Nil(t, buffer.Root.Right.Right.Right)
 		})
 
 		t.Run("LeftLeaf", func(t *testing.T) {
-			buffer.delete(10)
-			assert.Equal(t, 4, buffer.currentSize)
-			assert.Equal(t, 18, buffer.root.value)
-			assert.Equal(t, 0.310, buffer.root.priority)
-			assert.Equal(t, 15, buffer.root.left.value)
-			assert.Equal(t, 0.220, buffer.root.left.priority)
-			assert.Equal(t, 20, buffer.root.right.value)
-			assert.Equal(t, 0.300, buffer.root.right.priority)
-			assert.Nil(t, buffer.root.left.left)
-			assert.Nil(t, buffer.root.left.right)
-			assert.Equal(t, 30, buffer.root.right.right.value)
-			assert.Equal(t, 0.200, buffer.root.right.right.priority)
+			buffer.Delete(10)
+			assert.Equal(t, 4, buffer.Size)
+			assert.Equal(t, 18, buffer.Root.Value)
+			assert.Equal(t, 0.310, buffer.Root.Priority)
+			assert.Equal(t, 15, buffer.Root.Left.Value)
+			assert.Equal(t, 0.220, buffer.Root.Left.Priority)
+			assert.Equal(t, 20, buffer.Root.Right.Value)
+			assert.Equal(t, 0.300, buffer.Root.Right.Priority)
+			assert.Nil(t, buffer.Root.Left.Left)
+			assert.Nil(t, buffer.Root.Left.Right)
+			assert.Equal(t, 30, buffer.Root.Right.Right.Value)
+			assert.Equal(t, 0.200, buffer.Root.Right.Right.Priority)
 		})
 
 		t.Run("Middle", func(t *testing.T) {
-			buffer.delete(20)
-			assert.Equal(t, 3, buffer.currentSize)
-			assert.Equal(t, 18, buffer.root.value)
-			assert.Equal(t, 0.310, buffer.root.priority)
-			assert.Equal(t, 15, buffer.root.left.value)
-			assert.Equal(t, 0.220, buffer.root.left.priority)
-			assert.Equal(t, 30, buffer.root.right.value)
-			assert.Equal(t, 0.200, buffer.root.right.priority)
-			assert.Nil(t, buffer.root.right.right)
+			buffer.Delete(20)
+			assert.Equal(t, 3, buffer.Size)
+			assert.Equal(t, 18, buffer.Root.Value)
+			assert.Equal(t, 0.310, buffer.Root.Priority)
+			assert.Equal(t, 15, buffer.Root.Left.Value)
+			assert.Equal(t, 0.220, buffer.Root.Left.Priority)
+			assert.Equal(t, 30, buffer.Root.Right.Value)
+			assert.Equal(t, 0.200, buffer.Root.Right.Priority)
+			assert.Nil(t, buffer.Root.Right.Right)
 		})
 
 		t.Run("NotExisting", func(t *testing.T) {
-			buffer.delete(20)
-			assert.Equal(t, 3, buffer.currentSize)
-			assert.Equal(t, 18, buffer.root.value)
-			assert.Equal(t, 0.310, buffer.root.priority)
-			assert.Equal(t, 15, buffer.root.left.value)
-			assert.Equal(t, 0.220, buffer.root.left.priority)
-			assert.Equal(t, 30, buffer.root.right.value)
-			assert.Equal(t, 0.200, buffer.root.right.priority)
-			assert.Nil(t, buffer.root.right.right)
+			buffer.Delete(20)
+			assert.Equal(t, 3, buffer.Size)
+			assert.Equal(t, 18, buffer.Root.Value)
+			assert.Equal(t, 0.310, buffer.Root.Priority)
+			assert.Equal(t, 15, buffer.Root.Left.Value)
+			assert.Equal(t, 0.220, buffer.Root.Left.Priority)
+			assert.Equal(t, 30, buffer.Root.Right.Value)
+			assert.Equal(t, 0.200, buffer.Root.Right.Priority)
+			assert.Nil(t, buffer.Root.Right.Right)
 		})
 
 		t.Run("Root", func(t *testing.T) {
-			buffer.delete(18)
-			assert.Equal(t, 2, buffer.currentSize)
-			assert.Equal(t, 15, buffer.root.value)
-			assert.Equal(t, 0.220, buffer.root.priority)
-			assert.Nil(t, buffer.root.left)
-			assert.Equal(t, 30, buffer.root.right.value)
-			assert.Equal(t, 0.200, buffer.root.right.priority)
+			buffer.Delete(18)
+			assert.Equal(t, 2, buffer.Size)
+			assert.Equal(t, 15, buffer.Root.Value)
+			assert.Equal(t, 0.220, buffer.Root.Priority)
+			assert.Nil(t, buffer.Root.Left)
+			assert.Equal(t, 30, buffer.Root.Right.Value)
+			assert.Equal(t, 0.200, buffer.Root.Right.Priority)
 		})
 
 		t.Run("LastLeafOnRoot", func(t *testing.T) {
-			buffer.delete(30)
-			assert.Equal(t, 1, buffer.currentSize)
-			assert.Equal(t, 15, buffer.root.value)
-			assert.Equal(t, 0.220, buffer.root.priority)
-			assert.Nil(t, buffer.root.left)
-			assert.Nil(t, buffer.root.right)
+			buffer.Delete(30)
+			assert.Equal(t, 1, buffer.Size)
+			assert.Equal(t, 15, buffer.Root.Value)
+			assert.Equal(t, 0.220, buffer.Root.Priority)
+			assert.Nil(t, buffer.Root.Left)
+			assert.Nil(t, buffer.Root.Right)
 		})
 
 		t.Run("LastRoot", func(t *testing.T) {
-			buffer.delete(15)
-			assert.Equal(t, 0, buffer.currentSize)
-			assert.Nil(t, buffer.root)
+			buffer.Delete(15)
+			assert.Equal(t, 0, buffer.Size)
+			assert.Nil(t, buffer.Root)
 		})
 	})
 
 	t.Run("Float", func(t *testing.T) {
 		buffer := newTreapBuffer(floatTestComparator)
-		buffer.insert(newNode(30.30, 0.200))
-		buffer.insert(newNode(40.40, 0.100))
-		buffer.insert(newNode(20.20, 0.300))
-		buffer.insert(newNode(10.10, 0.210))
-		buffer.insert(newNode(15.15, 0.220))
-		buffer.insert(newNode(18.18, 0.310))
+		buffer.Insert(newNode(30.30, 0.200))
+		buffer.Insert(newNode(40.40, 0.100))
+		buffer.Insert(newNode(20.20, 0.300))
+		buffer.Insert(newNode(10.10, 0.210))
+		buffer.Insert(newNode(15.15, 0.220))
+		buffer.Insert(newNode(18.18, 0.310))
 
 		t.Run("RightLeaf", func(t *testing.T) {
-			buffer.delete(40)
-			assert.Equal(t, 5, buffer.currentSize)
-			assert.Equal(t, 18.18, buffer.root.value)
-			assert.Equal(t, 0.310, buffer.root.priority)
-			assert.Equal(t, 15.15, buffer.root.left.value)
-			assert.Equal(t, 0.220, buffer.root.left.priority)
-			assert.Equal(t, 20.20, buffer.root.right.value)
-			assert.Equal(t, 0.300, buffer.root.right.priority)
-			assert.Equal(t, 10.10, buffer.root.left.left.value)
-			assert.Equal(t, 0.210, buffer.root.left.left.priority)
-			assert.Nil(t, buffer.root.left.right)
-			assert.Equal(t, 30.30, buffer.root.right.right.value)
-			assert.Equal(t, 0.200, buffer.root.right.right.priority)
-			assert.Nil(t, buffer.root.right.left)
-			assert.Nil(t, buffer.root.right.right.right)
+			buffer.Delete(40)
+			assert.Equal(t, 5, buffer.Size)
+			assert.Equal(t, 18.18, buffer.Root.Value)
+			assert.Equal(t, 0.310, buffer.Root.Priority)
+			assert.Equal(t, 15.15, buffer.Root.Left.Value)
+			assert.Equal(t, 0.220, buffer.Root.Left.Priority)
+			assert.Equal(t, 20.20, buffer.Root.Right.Value)
+			assert.Equal(t, 0.300, buffer.Root.Right.Priority)
+			assert.Equal(t, 10.10, buffer.Root.Left.Left.Value)
+			assert.Equal(t, 0.210, buffer.Root.Left.Left.Priority)
+			assert.Nil(t, buffer.Root.Left.Right)
+			assert.Equal(t, 30.30, buffer.Root.Right.Right.Value)
+			assert.Equal(t, 0.200, buffer.Root.Right.Right.Priority)
+			assert.Nil(t, buffer.Root.Right.Left)
+			assert.Nil(t, buffer.Root.Right.Right.Right)
 		})
 
 		t.Run("LeftLeaf", func(t *testing.T) {
-			buffer.delete(10)
-			assert.Equal(t, 4, buffer.currentSize)
-			assert.Equal(t, 18.18, buffer.root.value)
-			assert.Equal(t, 0.310, buffer.root.priority)
-			assert.Equal(t, 15.15, buffer.root.left.value)
-			assert.Equal(t, 0.220, buffer.root.left.priority)
-			assert.Equal(t, 20.20, buffer.root.right.value)
-			assert.Equal(t, 0.300, buffer.root.right.priority)
-			assert.Nil(t, buffer.root.left.left)
-			assert.Nil(t, buffer.root.left.right)
-			assert.Equal(t, 30.30, buffer.root.right.right.value)
-			assert.Equal(t, 0.200, buffer.root.right.right.priority)
+			buffer.Delete(10)
+			assert.Equal(t, 4, buffer.Size)
+			assert.Equal(t, 18.18, buffer.Root.Value)
+			assert.Equal(t, 0.310, buffer.Root.Priority)
+			assert.Equal(t, 15.15, buffer.Root.Left.Value)
+			assert.Equal(t, 0.220, buffer.Root.Left.Priority)
+			assert.Equal(t, 20.20, buffer.Root.Right.Value)
+			assert.Equal(t, 0.300, buffer.Root.Right.Priority)
+			assert.Nil(t, buffer.Root.Left.Left)
+			assert.Nil(t, buffer.Root.Left.Right)
+			assert.Equal(t, 30.30, buffer.Root.Right.Right.Value)
+			assert.Equal(t, 0.200, buffer.Root.Right.Right.Priority)
 		})
 
 		t.Run("Middle", func(t *testing.T) {
-			buffer.delete(20)
-			assert.Equal(t, 3, buffer.currentSize)
-			assert.Equal(t, 18.18, buffer.root.value)
-			assert.Equal(t, 0.310, buffer.root.priority)
-			assert.Equal(t, 15.15, buffer.root.left.value)
-			assert.Equal(t, 0.220, buffer.root.left.priority)
-			assert.Equal(t, 30.30, buffer.root.right.value)
-			assert.Equal(t, 0.200, buffer.root.right.priority)
-			assert.Nil(t, buffer.root.right.right)
+			buffer.Delete(20)
+			assert.Equal(t, 3, buffer.Size)
+			assert.Equal(t, 18.18, buffer.Root.Value)
+			assert.Equal(t, 0.310, buffer.Root.Priority)
+			assert.Equal(t, 15.15, buffer.Root.Left.Value)
+			assert.Equal(t, 0.220, buffer.Root.Left.Priority)
+			assert.Equal(t, 30.30, buffer.Root.Right.Value)
+			assert.Equal(t, 0.200, buffer.Root.Right.Priority)
+			assert.Nil(t, buffer.Root.Right.Right)
 		})
 
 		t.Run("NotExisting", func(t *testing.T) {
-			buffer.delete(20)
-			assert.Equal(t, 3, buffer.currentSize)
-			assert.Equal(t, 18.18, buffer.root.value)
-			assert.Equal(t, 0.310, buffer.root.priority)
-			assert.Equal(t, 15.15, buffer.root.left.value)
-			assert.Equal(t, 0.220, buffer.root.left.priority)
-			assert.Equal(t, 30.30, buffer.root.right.value)
-			assert.Equal(t, 0.200, buffer.root.right.priority)
-			assert.Nil(t, buffer.root.right.right)
+			buffer.Delete(20)
+			assert.Equal(t, 3, buffer.Size)
+			assert.Equal(t, 18.18, buffer.Root.Value)
+			assert.Equal(t, 0.310, buffer.Root.Priority)
+			assert.Equal(t, 15.15, buffer.Root.Left.Value)
+			assert.Equal(t, 0.220, buffer.Root.Left.Priority)
+			assert.Equal(t, 30.30, buffer.Root.Right.Value)
+			assert.Equal(t, 0.200, buffer.Root.Right.Priority)
+			assert.Nil(t, buffer.Root.Right.Right)
 		})
 
 		t.Run("Root", func(t *testing.T) {
-			buffer.delete(18)
-			assert.Equal(t, 2, buffer.currentSize)
-			assert.Equal(t, 15.15, buffer.root.value)
-			assert.Equal(t, 0.220, buffer.root.priority)
-			assert.Nil(t, buffer.root.left)
-			assert.Equal(t, 30.30, buffer.root.right.value)
-			assert.Equal(t, 0.200, buffer.root.right.priority)
+			buffer.Delete(18)
+			assert.Equal(t, 2, buffer.Size)
+			assert.Equal(t, 15.15, buffer.Root.Value)
+			assert.Equal(t, 0.220, buffer.Root.Priority)
+			assert.Nil(t, buffer.Root.Left)
+			assert.Equal(t, 30.30, buffer.Root.Right.Value)
+			assert.Equal(t, 0.200, buffer.Root.Right.Priority)
 		})
 
 		t.Run("LastLeafOnRoot", func(t *testing.T) {
-			buffer.delete(30)
-			assert.Equal(t, 1, buffer.currentSize)
-			assert.Equal(t, 15.15, buffer.root.value)
-			assert.Equal(t, 0.220, buffer.root.priority)
-			assert.Nil(t, buffer.root.left)
-			assert.Nil(t, buffer.root.right)
+			buffer.Delete(30)
+			assert.Equal(t, 1, buffer.Size)
+			assert.Equal(t, 15.15, buffer.Root.Value)
+			assert.Equal(t, 0.220, buffer.Root.Priority)
+			assert.Nil(t, buffer.Root.Left)
+			assert.Nil(t, buffer.Root.Right)
 		})
 
 		t.Run("LastRoot", func(t *testing.T) {
-			buffer.delete(15)
-			assert.Equal(t, 0, buffer.currentSize)
-			assert.Nil(t, buffer.root)
+			buffer.Delete(15)
+			assert.Equal(t, 0, buffer.Size)
+			assert.Nil(t, buffer.Root)
 		})
 	})
 
 	t.Run("String", func(t *testing.T) {
 		buffer := newTreapBuffer(stringTestComparator)
-		buffer.insert(newNode("30", 0.200))
-		buffer.insert(newNode("40", 0.100))
-		buffer.insert(newNode("20", 0.300))
-		buffer.insert(newNode("10", 0.210))
-		buffer.insert(newNode("15", 0.220))
-		buffer.insert(newNode("18", 0.310))
+		buffer.Insert(newNode("30", 0.200))
+		buffer.Insert(newNode("40", 0.100))
+		buffer.Insert(newNode("20", 0.300))
+		buffer.Insert(newNode("10", 0.210))
+		buffer.Insert(newNode("15", 0.220))
+		buffer.Insert(newNode("18", 0.310))
 
 		t.Run("RightLeaf", func(t *testing.T) {
-			buffer.delete("40")
-			assert.Equal(t, 5, buffer.currentSize)
-			assert.Equal(t, "18", buffer.root.value)
-			assert.Equal(t, 0.310, buffer.root.priority)
-			assert.Equal(t, "15", buffer.root.left.value)
-			assert.Equal(t, 0.220, buffer.root.left.priority)
-			assert.Equal(t, "20", buffer.root.right.value)
-			assert.Equal(t, 0.300, buffer.root.right.priority)
-			assert.Equal(t, "10", buffer.root.left.left.value)
-			assert.Equal(t, 0.210, buffer.root.left.left.priority)
-			assert.Nil(t, buffer.root.left.right)
-			assert.Equal(t, "30", buffer.root.right.right.value)
-			assert.Equal(t, 0.200, buffer.root.right.right.priority)
-			assert.Nil(t, buffer.root.right.left)
-			assert.Nil(t, buffer.root.right.right.right)
+			buffer.Delete("40")
+			assert.Equal(t, 5, buffer.Size)
+			assert.Equal(t, "18", buffer.Root.Value)
+			assert.Equal(t, 0.310, buffer.Root.Priority)
+			assert.Equal(t, "15", buffer.Root.Left.Value)
+			assert.Equal(t, 0.220, buffer.Root.Left.Priority)
+			assert.Equal(t, "20", buffer.Root.Right.Value)
+			assert.Equal(t, 0.300, buffer.Root.Right.Priority)
+			assert.Equal(t, "10", buffer.Root.Left.Left.Value)
+			assert.Equal(t, 0.210, buffer.Root.Left.Left.Priority)
+			assert.Nil(t, buffer.Root.Left.Right)
+			assert.Equal(t, "30", buffer.Root.Right.Right.Value)
+			assert.Equal(t, 0.200, buffer.Root.Right.Right.Priority)
+			assert.Nil(t, buffer.Root.Right.Left)
+			assert.Nil(t, buffer.Root.Right.Right.Right)
 		})
 
 		t.Run("LeftLeaf", func(t *testing.T) {
-			buffer.delete("10")
-			assert.Equal(t, 4, buffer.currentSize)
-			assert.Equal(t, "18", buffer.root.value)
-			assert.Equal(t, 0.310, buffer.root.priority)
-			assert.Equal(t, "15", buffer.root.left.value)
-			assert.Equal(t, 0.220, buffer.root.left.priority)
-			assert.Equal(t, "20", buffer.root.right.value)
-			assert.Equal(t, 0.300, buffer.root.right.priority)
-			assert.Nil(t, buffer.root.left.left)
-			assert.Nil(t, buffer.root.left.right)
-			assert.Equal(t, "30", buffer.root.right.right.value)
-			assert.Equal(t, 0.200, buffer.root.right.right.priority)
+			buffer.Delete("10")
+			assert.Equal(t, 4, buffer.Size)
+			assert.Equal(t, "18", buffer.Root.Value)
+			assert.Equal(t, 0.310, buffer.Root.Priority)
+			assert.Equal(t, "15", buffer.Root.Left.Value)
+			assert.Equal(t, 0.220, buffer.Root.Left.Priority)
+			assert.Equal(t, "20", buffer.Root.Right.Value)
+			assert.Equal(t, 0.300, buffer.Root.Right.Priority)
+			assert.Nil(t, buffer.Root.Left.Left)
+			assert.Nil(t, buffer.Root.Left.Right)
+			assert.Equal(t, "30", buffer.Root.Right.Right.Value)
+			assert.Equal(t, 0.200, buffer.Root.Right.Right.Priority)
 		})
 
 		t.Run("Middle", func(t *testing.T) {
-			buffer.delete("20")
-			assert.Equal(t, 3, buffer.currentSize)
-			assert.Equal(t, "18", buffer.root.value)
-			assert.Equal(t, 0.310, buffer.root.priority)
-			assert.Equal(t, "15", buffer.root.left.value)
-			assert.Equal(t, 0.220, buffer.root.left.priority)
-			assert.Equal(t, "30", buffer.root.right.value)
-			assert.Equal(t, 0.200, buffer.root.right.priority)
-			assert.Nil(t, buffer.root.right.right)
+			buffer.Delete("20")
+			assert.Equal(t, 3, buffer.Size)
+			assert.Equal(t, "18", buffer.Root.Value)
+			assert.Equal(t, 0.310, buffer.Root.Priority)
+			assert.Equal(t, "15", buffer.Root.Left.Value)
+			assert.Equal(t, 0.220, buffer.Root.Left.Priority)
+			assert.Equal(t, "30", buffer.Root.Right.Value)
+			assert.Equal(t, 0.200, buffer.Root.Right.Priority)
+			assert.Nil(t, buffer.Root.Right.Right)
 		})
 
 		t.Run("NotExisting", func(t *testing.T) {
-			buffer.delete("20")
-			assert.Equal(t, 3, buffer.currentSize)
-			assert.Equal(t, "18", buffer.root.value)
-			assert.Equal(t, 0.310, buffer.root.priority)
-			assert.Equal(t, "15", buffer.root.left.value)
-			assert.Equal(t, 0.220, buffer.root.left.priority)
-			assert.Equal(t, "30", buffer.root.right.value)
-			assert.Equal(t, 0.200, buffer.root.right.priority)
-			assert.Nil(t, buffer.root.right.right)
+			buffer.Delete("20")
+			assert.Equal(t, 3, buffer.Size)
+			assert.Equal(t, "18", buffer.Root.Value)
+			assert.Equal(t, 0.310, buffer.Root.Priority)
+			assert.Equal(t, "15", buffer.Root.Left.Value)
+			assert.Equal(t, 0.220, buffer.Root.Left.Priority)
+			assert.Equal(t, "30", buffer.Root.Right.Value)
+			assert.Equal(t, 0.200, buffer.Root.Right.Priority)
+			assert.Nil(t, buffer.Root.Right.Right)
 		})
 
 		t.Run("Root", func(t *testing.T) {
-			buffer.delete("18")
-			assert.Equal(t, 2, buffer.currentSize)
-			assert.Equal(t, "15", buffer.root.value)
-			assert.Equal(t, 0.220, buffer.root.priority)
-			assert.Nil(t, buffer.root.left)
-			assert.Equal(t, "30", buffer.root.right.value)
-			assert.Equal(t, 0.200, buffer.root.right.priority)
+			buffer.Delete("18")
+			assert.Equal(t, 2, buffer.Size)
+			assert.Equal(t, "15", buffer.Root.Value)
+			assert.Equal(t, 0.220, buffer.Root.Priority)
+			assert.Nil(t, buffer.Root.Left)
+			assert.Equal(t, "30", buffer.Root.Right.Value)
+			assert.Equal(t, 0.200, buffer.Root.Right.Priority)
 		})
 
 		t.Run("LastLeafOnRoot", func(t *testing.T) {
-			buffer.delete("30")
-			assert.Equal(t, 1, buffer.currentSize)
-			assert.Equal(t, "15", buffer.root.value)
-			assert.Equal(t, 0.220, buffer.root.priority)
-			assert.Nil(t, buffer.root.left)
-			assert.Nil(t, buffer.root.right)
+			buffer.Delete("30")
+			assert.Equal(t, 1, buffer.Size)
+			assert.Equal(t, "15", buffer.Root.Value)
+			assert.Equal(t, 0.220, buffer.Root.Priority)
+			assert.Nil(t, buffer.Root.Left)
+			assert.Nil(t, buffer.Root.Right)
 		})
 
 		t.Run("LastRoot", func(t *testing.T) {
-			buffer.delete("15")
-			assert.Equal(t, 0, buffer.currentSize)
-			assert.Nil(t, buffer.root)
+			buffer.Delete("15")
+			assert.Equal(t, 0, buffer.Size)
+			assert.Nil(t, buffer.Root)
 		})
 	})
 
 	t.Run("Struct", func(t *testing.T) {
 		buffer := newTreapBuffer(structTestComparator)
-		buffer.insert(newNode(&testStruct{id: 30, name: "Bruce"}, 0.200))
-		buffer.insert(newNode(&testStruct{id: 40, name: "Clark"}, 0.100))
-		buffer.insert(newNode(&testStruct{id: 20, name: "Selina"}, 0.300))
-		buffer.insert(newNode(&testStruct{id: 10, name: "Pamela"}, 0.210))
-		buffer.insert(newNode(&testStruct{id: 15, name: "Lex"}, 0.220))
-		buffer.insert(newNode(&testStruct{id: 18, name: "Hal"}, 0.310))
+		buffer.Insert(newNode(&testStruct{id: 30, name: "Bruce"}, 0.200))
+		buffer.Insert(newNode(&testStruct{id: 40, name: "Clark"}, 0.100))
+		buffer.Insert(newNode(&testStruct{id: 20, name: "Selina"}, 0.300))
+		buffer.Insert(newNode(&testStruct{id: 10, name: "Pamela"}, 0.210))
+		buffer.Insert(newNode(&testStruct{id: 15, name: "Lex"}, 0.220))
+		buffer.Insert(newNode(&testStruct{id: 18, name: "Hal"}, 0.310))
 
 		t.Run("RightLeaf", func(t *testing.T) {
-			buffer.delete(&testStruct{id: 40, name: "Clark"})
-			assert.Equal(t, 5, buffer.currentSize)
-			assert.Equal(t, 18, buffer.root.value.id)
-			assert.Equal(t, "Hal", buffer.root.value.name)
-			assert.Equal(t, 0.310, buffer.root.priority)
-			assert.Equal(t, 15, buffer.root.left.value.id)
-			assert.Equal(t, "Lex", buffer.root.left.value.name)
-			assert.Equal(t, 0.220, buffer.root.left.priority)
-			assert.Equal(t, 20, buffer.root.right.value.id)
-			assert.Equal(t, "Selina", buffer.root.right.value.name)
-			assert.Equal(t, 0.300, buffer.root.right.priority)
-			assert.Equal(t, 10, buffer.root.left.left.value.id)
-			assert.Equal(t, "Pamela", buffer.root.left.left.value.name)
-			assert.Equal(t, 0.210, buffer.root.left.left.priority)
-			assert.Nil(t, buffer.root.left.right)
-			assert.Equal(t, 30, buffer.root.right.right.value.id)
-			assert.Equal(t, "Bruce", buffer.root.right.right.value.name)
-			assert.Equal(t, 0.200, buffer.root.right.right.priority)
-			assert.Nil(t, buffer.root.right.left)
-			assert.Nil(t, buffer.root.right.right.right)
+			buffer.Delete(&testStruct{id: 40, name: "Clark"})
+			assert.Equal(t, 5, buffer.Size)
+			assert.Equal(t, 18, buffer.Root.Value.id)
+			assert.Equal(t, "Hal", buffer.Root.Value.name)
+			assert.Equal(t, 0.310, buffer.Root.Priority)
+			assert.Equal(t, 15, buffer.Root.Left.Value.id)
+			assert.Equal(t, "Lex", buffer.Root.Left.Value.name)
+			assert.Equal(t, 0.220, buffer.Root.Left.Priority)
+			assert.Equal(t, 20, buffer.Root.Right.Value.id)
+			assert.Equal(t, "Selina", buffer.Root.Right.Value.name)
+			assert.Equal(t, 0.300, buffer.Root.Right.Priority)
+			assert.Equal(t, 10, buffer.Root.Left.Left.Value.id)
+			assert.Equal(t, "Pamela", buffer.Root.Left.Left.Value.name)
+			assert.Equal(t, 0.210, buffer.Root.Left.Left.Priority)
+			assert.Nil(t, buffer.Root.Left.Right)
+			assert.Equal(t, 30, buffer.Root.Right.Right.Value.id)
+			assert.Equal(t, "Bruce", buffer.Root.Right.Right.Value.name)
+			assert.Equal(t, 0.200, buffer.Root.Right.Right.Priority)
+			assert.Nil(t, buffer.Root.Right.Left)
+			assert.Nil(t, buffer.Root.Right.Right.Right)
 		})
 
 		t.Run("LeftLeaf", func(t *testing.T) {
-			buffer.delete(&testStruct{id: 10, name: "Pamela"})
-			assert.Equal(t, 4, buffer.currentSize)
-			assert.Equal(t, 18, buffer.root.value.id)
-			assert.Equal(t, "Hal", buffer.root.value.name)
-			assert.Equal(t, 0.310, buffer.root.priority)
-			assert.Equal(t, 15, buffer.root.left.value.id)
-			assert.Equal(t, "Lex", buffer.root.left.value.name)
-			assert.Equal(t, 0.220, buffer.root.left.priority)
-			assert.Equal(t, 20, buffer.root.right.value.id)
-			assert.Equal(t, "Selina", buffer.root.right.value.name)
-			assert.Equal(t, 0.300, buffer.root.right.priority)
-			assert.Nil(t, buffer.root.left.left)
-			assert.Nil(t, buffer.root.left.right)
-			assert.Equal(t, 30, buffer.root.right.right.value.id)
-			assert.Equal(t, "Bruce", buffer.root.right.right.value.name)
-			assert.Equal(t, 0.200, buffer.root.right.right.priority)
+			buffer.Delete(&testStruct{id: 10, name: "Pamela"})
+			assert.Equal(t, 4, buffer.Size)
+			assert.Equal(t, 18, buffer.Root.Value.id)
+			assert.Equal(t, "Hal", buffer.Root.Value.name)
+			assert.Equal(t, 0.310, buffer.Root.Priority)
+			assert.Equal(t, 15, buffer.Root.Left.Value.id)
+			assert.Equal(t, "Lex", buffer.Root.Left.Value.name)
+			assert.Equal(t, 0.220, buffer.Root.Left.Priority)
+			assert.Equal(t, 20, buffer.Root.Right.Value.id)
+			assert.Equal(t, "Selina", buffer.Root.Right.Value.name)
+			assert.Equal(t, 0.300, buffer.Root.Right.Priority)
+			assert.Nil(t, buffer.Root.Left.Left)
+			assert.Nil(t, buffer.Root.Left.Right)
+			assert.Equal(t, 30, buffer.Root.Right.Right.Value.id)
+			assert.Equal(t, "Bruce", buffer.Root.Right.Right.Value.name)
+			assert.Equal(t, 0.200, buffer.Root.Right.Right.Priority)
 		})
 
 		t.Run("Middle", func(t *testing.T) {
-			buffer.delete(&testStruct{id: 20, name: "Selina"})
-			assert.Equal(t, 3, buffer.currentSize)
-			assert.Equal(t, 18, buffer.root.value.id)
-			assert.Equal(t, "Hal", buffer.root.value.name)
-			assert.Equal(t, 0.310, buffer.root.priority)
-			assert.Equal(t, 15, buffer.root.left.value.id)
-			assert.Equal(t, "Lex", buffer.root.left.value.name)
-			assert.Equal(t, 0.220, buffer.root.left.priority)
-			assert.Equal(t, 30, buffer.root.right.value.id)
-			assert.Equal(t, "Bruce", buffer.root.right.value.name)
-			assert.Equal(t, 0.200, buffer.root.right.priority)
-			assert.Nil(t, buffer.root.right.right)
+			buffer.Delete(&testStruct{id: 20, name: "Selina"})
+			assert.Equal(t, 3, buffer.Size)
+			assert.Equal(t, 18, buffer.Root.Value.id)
+			assert.Equal(t, "Hal", buffer.Root.Value.name)
+			assert.Equal(t, 0.310, buffer.Root.Priority)
+			assert.Equal(t, 15, buffer.Root.Left.Value.id)
+			assert.Equal(t, "Lex", buffer.Root.Left.Value.name)
+			assert.Equal(t, 0.220, buffer.Root.Left.Priority)
+			assert.Equal(t, 30, buffer.Root.Right.Value.id)
+			assert.Equal(t, "Bruce", buffer.Root.Right.Value.name)
+			assert.Equal(t, 0.200, buffer.Root.Right.Priority)
+			assert.Nil(t, buffer.Root.Right.Right)
 		})
 
 		t.Run("NotExisting", func(t *testing.T) {
-			buffer.delete(&testStruct{id: 20, name: "Selina"})
-			assert.Equal(t, 3, buffer.currentSize)
-			assert.Equal(t, 18, buffer.root.value.id)
-			assert.Equal(t, "Hal", buffer.root.value.name)
-			assert.Equal(t, 0.310, buffer.root.priority)
-			assert.Equal(t, 15, buffer.root.left.value.id)
-			assert.Equal(t, "Lex", buffer.root.left.value.name)
-			assert.Equal(t, 0.220, buffer.root.left.priority)
-			assert.Equal(t, 30, buffer.root.right.value.id)
-			assert.Equal(t, "Bruce", buffer.root.right.value.name)
-			assert.Equal(t, 0.200, buffer.root.right.priority)
-			assert.Nil(t, buffer.root.right.right)
+			buffer.Delete(&testStruct{id: 20, name: "Selina"})
+			assert.Equal(t, 3, buffer.Size)
+			assert.Equal(t, 18, buffer.Root.Value.id)
+			assert.Equal(t, "Hal", buffer.Root.Value.name)
+			assert.Equal(t, 0.310, buffer.Root.Priority)
+			assert.Equal(t, 15, buffer.Root.Left.Value.id)
+			assert.Equal(t, "Lex", buffer.Root.Left.Value.name)
+			assert.Equal(t, 0.220, buffer.Root.Left.Priority)
+			assert.Equal(t, 30, buffer.Root.Right.Value.id)
+			assert.Equal(t, "Bruce", buffer.Root.Right.Value.name)
+			assert.Equal(t, 0.200, buffer.Root.Right.Priority)
+			assert.Nil(t, buffer.Root.Right.Right)
 		})
 
 		t.Run("Root", func(t *testing.T) {
-			buffer.delete(&testStruct{id: 18, name: "Hal"})
-			assert.Equal(t, 2, buffer.currentSize)
-			assert.Equal(t, 15, buffer.root.value.id)
-			assert.Equal(t, "Lex", buffer.root.value.name)
-			assert.Equal(t, 0.220, buffer.root.priority)
-			assert.Nil(t, buffer.root.left)
-			assert.Equal(t, 30, buffer.root.right.value.id)
-			assert.Equal(t, "Bruce", buffer.root.right.value.name)
-			assert.Equal(t, 0.200, buffer.root.right.priority)
+			buffer.Delete(&testStruct{id: 18, name: "Hal"})
+			assert.Equal(t, 2, buffer.Size)
+			assert.Equal(t, 15, buffer.Root.Value.id)
+			assert.Equal(t, "Lex", buffer.Root.Value.name)
+			assert.Equal(t, 0.220, buffer.Root.Priority)
+			assert.Nil(t, buffer.Root.Left)
+			assert.Equal(t, 30, buffer.Root.Right.Value.id)
+			assert.Equal(t, "Bruce", buffer.Root.Right.Value.name)
+			assert.Equal(t, 0.200, buffer.Root.Right.Priority)
 		})
 
 		t.Run("LastLeafOnRoot", func(t *testing.T) {
-			buffer.delete(&testStruct{id: 30, name: "Bruce"})
-			assert.Equal(t, 1, buffer.currentSize)
-			assert.Equal(t, 15, buffer.root.value.id)
-			assert.Equal(t, "Lex", buffer.root.value.name)
-			assert.Equal(t, 0.220, buffer.root.priority)
-			assert.Nil(t, buffer.root.left)
-			assert.Nil(t, buffer.root.right)
+			buffer.Delete(&testStruct{id: 30, name: "Bruce"})
+			assert.Equal(t, 1, buffer.Size)
+			assert.Equal(t, 15, buffer.Root.Value.id)
+			assert.Equal(t, "Lex", buffer.Root.Value.name)
+			assert.Equal(t, 0.220, buffer.Root.Priority)
+			assert.Nil(t, buffer.Root.Left)
+			assert.Nil(t, buffer.Root.Right)
 		})
 
 		t.Run("LastRoot", func(t *testing.T) {
-			buffer.delete(&testStruct{id: 15, name: "Lex"})
-			assert.Equal(t, 0, buffer.currentSize)
-			assert.Nil(t, buffer.root)
+			buffer.Delete(&testStruct{id: 15, name: "Lex"})
+			assert.Equal(t, 0, buffer.Size)
+			assert.Nil(t, buffer.Root)
 		})
 	})
 }
 
+func TestDeleteFound(t *testing.T) {
+	buffer := newTestPrintBuffer()
+	assert.True(t, buffer.Delete(20))
+	assert.False(t, buffer.Delete(20))
+	assert.False(t, buffer.Delete(25))
+	assert.Equal(t, 3, buffer.Size)
+}
+
 func BenchmarkContains(b *testing.B) {
 	b.Run("Int", func(b *testing.B) {
 		lengths := []int{1_000, 10_000, 100_000, 1_000_000}
@@ -1293,11 +1303,11 @@ func BenchmarkContains(b *testing.B) {
 				stream := newTestIntStream(length, length-1)
 				buffer := newTreapBuffer(intTestComparator)
 				for i := 0; i < length; i++ {
-					buffer.insert(newNode(stream[i], rand.Float64()))
+					buffer.Insert(newNode(stream[i], rand.Float64()))
 				}
 				b.ResetTimer()
 				for _, element := range stream {
-					buffer.contains(element)
+					buffer.Contains(element)
 				}
 			})
 		}
@@ -1310,11 +1320,11 @@ func BenchmarkContains(b *testing.B) {
 				stream := newTestFloatStream(length, length-1)
 				buffer := newTreapBuffer(floatTestComparator)
 				for i := 0; i < length; i++ {
-					buffer.insert(newNode(stream[i], rand.Float64()))
+					buffer.Insert(newNode(stream[i], rand.Float64()))
 				}
 				b.ResetTimer()
 				for _, element := range stream {
-					buffer.contains(element)
+					buffer.Contains(element)
 				}
 			})
 		}
@@ -1327,11 +1337,11 @@ func BenchmarkContains(b *testing.B) {
 				stream := newTestStringStream(length, length-1)
 				buffer := newTreapBuffer(stringTestComparator)
 				for i := 0; i < length; i++ {
-					buffer.insert(newNode(stream[i], rand.Float64()))
+					buffer.Insert(newNode(stream[i], rand.Float64()))
 				}
 				b.ResetTimer()
 				for _, element := range stream {
-					buffer.contains(element)
+					buffer.Contains(element)
 				}
 			})
 		}
@@ -1344,11 +1354,11 @@ func BenchmarkContains(b *testing.B) {
 				stream := newTestStructStream(length, length-1)
 				buffer := newTreapBuffer(structTestComparator)
 				for i := 0; i < length; i++ {
-					buffer.insert(newNode(stream[i], rand.Float64()))
+					buffer.Insert(newNode(stream[i], rand.Float64()))
 				}
 				b.ResetTimer()
 				for _, element := range stream {
-					buffer.contains(element)
+					buffer.Contains(element)
 				}
 			})
 		}
@@ -1369,7 +1379,7 @@ Root: <nil>
 
 	t.Run("SingleNode", func(t *testing.T) {
 		buffer := newTreapBuffer(intTestComparator)
-		buffer.insert(newNode(30, 0.200))
+		buffer.Insert(newNode(30, 0.200))
 		writerBuffer := new(bytes.Buffer)
 		buffer.printBasicInfo(writerBuffer)
 
@@ -1379,3 +1389,171 @@ Root: <Value: 30, Priority: 0.200000>
 		assert.Equal(t, expectedOutput, writerBuffer.String())
 	})
 }
+
+func newTestPrintBuffer() *treapBuffer[int] {
+	buffer := newTreapBuffer(intTestComparator)
+	buffer.Insert(newNode(30, 0.200))
+	buffer.Insert(newNode(40, 0.100))
+	buffer.Insert(newNode(20, 0.300))
+	buffer.Insert(newNode(10, 0.210))
+	return buffer
+}
+
+func TestPrintInOrder(t *testing.T) {
+	t.Run("Empty", func(t *testing.T) {
+		buffer := newTreapBuffer(intTestComparator)
+		writerBuffer := new(bytes.Buffer)
+		buffer.printInOrder(writerBuffer)
+		assert.Equal(t, "", writerBuffer.String())
+	})
+
+	t.Run("MultipleNodes", func(t *testing.T) {
+		writerBuffer := new(bytes.Buffer)
+		newTestPrintBuffer().printInOrder(writerBuffer)
+
+		expectedOutput := `<Value: 10, Priority: 0.210000>
+<Value: 20, Priority: 0.300000>
+<Value: 30, Priority: 0.200000>
+<Value: 40, Priority: 0.100000>
+`
+		assert.Equal(t, expectedOutput, writerBuffer.String())
+	})
+}
+
+func TestPrintTree(t *testing.T) {
+	t.Run("Empty", func(t *testing.T) {
+		buffer := newTreapBuffer(intTestComparator)
+		writerBuffer := new(bytes.Buffer)
+		buffer.printTree(writerBuffer)
+		assert.Equal(t, "<nil>\n", writerBuffer.String())
+	})
+
+	t.Run("MultipleNodes", func(t *testing.T) {
+		writerBuffer := new(bytes.Buffer)
+		newTestPrintBuffer().printTree(writerBuffer)
+
+		expectedOutput := `<Value: 20, Priority: 0.300000>
+  L: <Value: 10, Priority: 0.210000>
+  R: <Value: 30, Priority: 0.200000>
+    R: <Value: 40, Priority: 0.100000>
+`
+		assert.Equal(t, expectedOutput, writerBuffer.String())
+	})
+}
+
+func TestPrintDOT(t *testing.T) {
+	t.Run("Empty", func(t *testing.T) {
+		buffer := newTreapBuffer(intTestComparator)
+		writerBuffer := new(bytes.Buffer)
+		buffer.printDOT(writerBuffer)
+		assert.Equal(t, "digraph treap {\n}\n", writerBuffer.String())
+	})
+
+	t.Run("MultipleNodes", func(t *testing.T) {
+		writerBuffer := new(bytes.Buffer)
+		newTestPrintBuffer().printDOT(writerBuffer)
+
+		expectedOutput := `digraph treap {
+	n0 [label="20\n0.300000"];
+	n0 -> n1 [label="L"];
+	n1 [label="10\n0.210000"];
+	n0 -> n2 [label="R"];
+	n2 [label="30\n0.200000"];
+	n2 -> n3 [label="R"];
+	n3 [label="40\n0.100000"];
+}
+`
+		assert.Equal(t, expectedOutput, writerBuffer.String())
+	})
+
+	t.Run("QuotedValue", func(t *testing.T) {
+		buffer := newTreapBuffer(stringTestComparator)
+		buffer.Insert(newNode(`say "hi"`, 0.5))
+		writerBuffer := new(bytes.Buffer)
+		buffer.printDOT(writerBuffer)
+		assert.Contains(t, writerBuffer.String(), `n0 [label="say \"hi\"\n0.500000"];`)
+	})
+}
+
+func TestAscend(t *testing.T) {
+	t.Run("Empty", func(t *testing.T) {
+		buffer := newTreapBuffer(intTestComparator)
+		buffer.Ascend(func(node *treap.Node[int, *occurrences]) bool {
+			t.Fatal("yield called on empty buffer")
+			return true
+		})
+	})
+
+	t.Run("All", func(t *testing.T) {
+		values := make([]int, 0)
+		newTestPrintBuffer().Ascend(func(node *treap.Node[int, *occurrences]) bool {
+			values = append(values, node.Value)
+			return true
+		})
+		assert.Equal(t, []int{10, 20, 30, 40}, values)
+	})
+
+	t.Run("Stop", func(t *testing.T) {
+		values := make([]int, 0)
+		newTestPrintBuffer().Ascend(func(node *treap.Node[int, *occurrences]) bool {
+			values = append(values, node.Value)
+			return node.Value < 20
+		})
+		assert.Equal(t, []int{10, 20}, values)
+	})
+}
+
+func TestRank(t *testing.T) {
+	t.Run("Empty", func(t *testing.T) {
+		buffer := newTreapBuffer(intTestComparator)
+		assert.Equal(t, 0, buffer.Rank(10))
+	})
+
+	t.Run("MultipleNodes", func(t *testing.T) {
+		buffer := newTestPrintBuffer()
+		assert.Equal(t, 0, buffer.Rank(5))
+		assert.Equal(t, 0, buffer.Rank(10))
+		assert.Equal(t, 1, buffer.Rank(15))
+		assert.Equal(t, 2, buffer.Rank(30))
+		assert.Equal(t, 3, buffer.Rank(40))
+		assert.Equal(t, 4, buffer.Rank(45))
+	})
+
+	t.Run("AfterDelete", func(t *testing.T) {
+		buffer := newTestPrintBuffer()
+		buffer.Delete(20)
+		assert.Equal(t, 1, buffer.Rank(30))
+		assert.Equal(t, 3, buffer.Root.SubtreeSize())
+	})
+}
+
+func TestSelectAt(t *testing.T) {
+	t.Run("Empty", func(t *testing.T) {
+		buffer := newTreapBuffer(intTestComparator)
+		assert.Nil(t, buffer.Select(0))
+	})
+
+	t.Run("MultipleNodes", func(t *testing.T) {
+		buffer := newTestPrintBuffer()
+		assert.Equal(t, 10, buffer.Select(0).Value)
+		assert.Equal(t, 20, buffer.Select(1).Value)
+		assert.Equal(t, 30, buffer.Select(2).Value)
+		assert.Equal(t, 40, buffer.Select(3).Value)
+		assert.Nil(t, buffer.Select(-1))
+		assert.Nil(t, buffer.Select(4))
+	})
+
+	t.Run("Random", func(t *testing.T) {
+		buffer := newTreapBuffer(intTestComparator)
+		for _, element := range rand.Perm(1_000) {
+			buffer.Insert(newNode(element, rand.Float64()))
+		}
+		for _, element := range rand.Perm(1_000)[:500] {
+			buffer.Delete(element)
+		}
+		assert.Nil(t, buffer.Validate())
+		for k := 0; k < buffer.Size; k++ {
+			assert.Equal(t, k, buffer.Rank(buffer.Select(k).Value))
+		}
+	})
+}